@@ -0,0 +1,60 @@
+package api
+
+import (
+	"net/http"
+	"time"
+
+	"github.com/go-openapi/strfmt"
+	"github.com/labstack/echo/v4"
+	"github.com/mtfelian/gjg-test-task/game"
+	"github.com/mtfelian/gjg-test-task/storage/model"
+)
+
+// GetLevelResponse represents response for GetLevel handler
+type GetLevelResponse struct {
+	LevelID       strfmt.UUID         `json:"id"`
+	X             int                 `json:"x"`
+	Y             int                 `json:"y"`
+	Maze          Maze                `json:"maze"`
+	Teleporters   []game.Teleporter   `json:"teleporters,omitempty"`
+	PeriodicTraps []game.PeriodicTrap `json:"periodic_traps,omitempty"`
+	Enemies       []game.Enemy        `json:"enemies,omitempty"`
+	Topology      string              `json:"topology"`
+	LengthScore   int                 `json:"length_score"`
+	MoveCost      int                 `json:"move_cost"`
+	Damage        int                 `json:"damage"`
+	Solvable      bool                `json:"solvable"`
+	Author        string              `json:"author"`
+	CreatedAt     time.Time           `json:"created_at"`
+}
+
+// levelResponse converts the storage layer level to the API response
+func levelResponse(level model.Level) GetLevelResponse {
+	position := game.FromStorage(level)
+	return GetLevelResponse{
+		LevelID:       strfmt.UUID(level.ID.String()),
+		X:             level.X,
+		Y:             level.Y,
+		Maze:          position.Maze,
+		Teleporters:   position.Teleporters,
+		PeriodicTraps: position.PeriodicTraps,
+		Enemies:       position.Enemies,
+		Topology:      position.Topology,
+		LengthScore:   level.LengthScore,
+		MoveCost:      level.MoveCost,
+		Damage:        level.Damage,
+		Solvable:      level.Solvable,
+		Author:        level.Author,
+		CreatedAt:     level.CreatedAt,
+	}
+}
+
+// GetLevel is an API handler to get the stored level
+func GetLevel(c echo.Context) error {
+	level, code, Err := getLevel(c.Param("id"))
+	if Err != nil {
+		return c.JSON(code, *Err)
+	}
+
+	return c.JSON(http.StatusOK, levelResponse(*level))
+}
@@ -0,0 +1,22 @@
+package api
+
+import (
+	"net/http"
+
+	"github.com/labstack/echo/v4"
+)
+
+// GetLevelHP is an API handler to get the analysis of the level survivability depending on the starting HP
+func GetLevelHP(c echo.Context) error {
+	position, code, Err := getPosition(c.Param("id"))
+	if Err != nil {
+		return c.JSON(code, *Err)
+	}
+
+	analysis, Err := position.AnalyzeHP()
+	if Err != nil {
+		code = http.StatusUnprocessableEntity
+		return c.JSON(code, *Err)
+	}
+	return c.JSON(http.StatusOK, analysis)
+}
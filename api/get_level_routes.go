@@ -0,0 +1,33 @@
+package api
+
+import (
+	"net/http"
+
+	"github.com/labstack/echo/v4"
+)
+
+// GetLevelRoutesResponse represents response for GetLevelRoutes handler
+type GetLevelRoutesResponse struct {
+	Routes []GetLevelSolutionResponse `json:"routes"` // ordered by move cost, from the fastest to the safest
+}
+
+// GetLevelRoutes is an API handler to get the routes of the level which are not dominated
+// by another route on both move cost and damage taken
+func GetLevelRoutes(c echo.Context) error {
+	position, code, Err := getPosition(c.Param("id"))
+	if Err != nil {
+		return c.JSON(code, *Err)
+	}
+
+	paths, Err := position.Routes()
+	if Err != nil {
+		code = http.StatusUnprocessableEntity
+		return c.JSON(code, *Err)
+	}
+
+	r := GetLevelRoutesResponse{Routes: make([]GetLevelSolutionResponse, len(paths))}
+	for k, path := range paths {
+		r.Routes[k] = solutionResponse(path)
+	}
+	return c.JSON(http.StatusOK, r)
+}
@@ -0,0 +1,117 @@
+package api
+
+import (
+	"fmt"
+	"net/http"
+
+	"github.com/labstack/echo/v4"
+	"github.com/mtfelian/gjg-test-task/game"
+	"github.com/mtfelian/gjg-test-task/service"
+)
+
+// GetLevelSolutionResponse represents response for GetLevelSolution handler
+type GetLevelSolutionResponse struct {
+	Path        []game.JI       `json:"path"`
+	Length      int             `json:"length"`
+	Cost        int             `json:"cost"`
+	Waits       int             `json:"waits"`
+	Damage      int             `json:"damage"`
+	Healed      int             `json:"healed"`
+	RemainingHP int             `json:"remaining_hp"`
+	Traps       []game.TrapPass `json:"traps"` // ticks on which the player passes traps
+	EnemyHits   []game.EnemyHit `json:"enemy_hits"`
+	Enemies     [][]game.JI     `json:"enemies,omitempty"` // cells of each enemy at each step of the path
+
+	ExpectedDamage float64 `json:"expected_damage"`
+	SurvivalChance float64 `json:"survival_chance"`
+}
+
+// MaxStartingHPFactor is a max ratio of the starting_hp query parameter to the configured starting HP.
+// Each HP multiplies the number of states the solver explores.
+const MaxStartingHPFactor = 4
+
+// GetLevelSolutionParams represents query parameters for GetLevelSolution handler
+type GetLevelSolutionParams struct {
+	MinSurvival float64 // zero means damage is always dealt
+	StartingHP  int     // zero means the configured one
+	Solver      string  // empty means bfs
+}
+
+// bindGetLevelSolutionParams from the query of the request c, starting_hp is limited according to the game rules
+func bindGetLevelSolutionParams(c echo.Context, gameRules *game.Rules) (p GetLevelSolutionParams, err error) {
+	if err = echo.QueryParamsBinder(c).
+		Float64("min_survival", &p.MinSurvival).
+		Int("starting_hp", &p.StartingHP).
+		String("solver", &p.Solver).
+		BindError(); err != nil {
+		return
+	}
+
+	switch {
+	case p.MinSurvival < 0 || p.MinSurvival > 1:
+		return p, fmt.Errorf("min_survival should be from 0 to 1, got: %v", p.MinSurvival)
+	case p.StartingHP < 0 || p.StartingHP > MaxStartingHPFactor*gameRules.StartingHP:
+		return p, fmt.Errorf("starting_hp should be from 0 to %d, got: %d",
+			MaxStartingHPFactor*gameRules.StartingHP, p.StartingHP)
+	case p.Solver != "" && !game.IsValidSolver(p.Solver):
+		return p, fmt.Errorf("solver should be %s or %s, got: %s", game.SolverBFS, game.SolverAStar, p.Solver)
+	}
+	return
+}
+
+// GetLevelSolution is an API handler to get the minimum survivable path of the level.
+// If min_survival query parameter is given, random traps are considered to hit with their chances
+// and the minimum path survived with at least this probability is returned.
+// If starting_hp query parameter is given, it replaces the configured starting HP, but can not exceed
+// MaxStartingHPFactor times of it.
+// The solver query parameter selects the search algorithm: bfs (default) or astar, which is faster on large mazes.
+func GetLevelSolution(c echo.Context) error {
+	position, code, Err := getPosition(c.Param("id"))
+	if Err != nil {
+		return c.JSON(code, *Err)
+	}
+	p, err := bindGetLevelSolutionParams(c, position.Rules)
+	if err != nil {
+		return c.JSON(http.StatusUnprocessableEntity, game.Error{Code: service.ErrValidationRequest, Message: err.Error()})
+	}
+	if p.StartingHP > 0 {
+		position.Rules.StartingHP = p.StartingHP
+	}
+
+	var path *game.Path
+	switch {
+	case p.MinSurvival > 0 && p.Solver == game.SolverAStar:
+		path, Err = position.SolveAStarWithChance(p.MinSurvival)
+	case p.MinSurvival > 0:
+		path, Err = position.SolveWithChance(p.MinSurvival)
+	case p.Solver == game.SolverAStar:
+		path, Err = position.SolveAStar()
+	default:
+		path, Err = position.Solve()
+	}
+	if Err != nil {
+		code = http.StatusUnprocessableEntity
+		return c.JSON(code, *Err)
+	}
+
+	return c.JSON(http.StatusOK, solutionResponse(path))
+}
+
+// solutionResponse converts the path to the API response
+func solutionResponse(path *game.Path) GetLevelSolutionResponse {
+	return GetLevelSolutionResponse{
+		Path:        path.Cells,
+		Length:      path.Length,
+		Cost:        path.Cost,
+		Waits:       path.Waits,
+		Damage:      path.Damage,
+		Healed:      path.Healed,
+		RemainingHP: path.RemainingHP,
+		Traps:       path.Traps,
+		EnemyHits:   path.EnemyHits,
+		Enemies:     path.Enemies,
+
+		ExpectedDamage: path.ExpectedDamage,
+		SurvivalChance: path.SurvivalChance,
+	}
+}
@@ -0,0 +1,73 @@
+package api
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+
+	"github.com/labstack/echo/v4"
+	"github.com/mtfelian/gjg-test-task/game"
+	"github.com/mtfelian/gjg-test-task/service"
+)
+
+// trace size limits
+const (
+	DefaultTraceMaxEvents = 10000
+	MaxTraceMaxEvents     = 100000
+)
+
+// GetLevelSolutionTraceResponse represents response for GetLevelSolutionTrace handler
+type GetLevelSolutionTraceResponse struct {
+	Events    []game.TraceEvent         `json:"events"`    // in order of their occurrence
+	Truncated bool                      `json:"truncated"` // true if events after max_events are not returned
+	Solution  *GetLevelSolutionResponse `json:"solution"`  // null if there is no survivable path
+}
+
+// bindMaxEvents binds the max_events query parameter of the request c
+func bindMaxEvents(c echo.Context) (maxEvents int, err error) {
+	maxEvents = DefaultTraceMaxEvents
+	if err = echo.QueryParamsBinder(c).Int("max_events", &maxEvents).BindError(); err != nil {
+		return
+	}
+	if maxEvents < 1 || maxEvents > MaxTraceMaxEvents {
+		return maxEvents, fmt.Errorf("max_events should be from 1 to %d, got: %d", MaxTraceMaxEvents, maxEvents)
+	}
+	return
+}
+
+// GetLevelSolutionTrace is an API handler to get the sequence of events of the minimum survivable path search,
+// it allows to see how the solver explores the maze. The starting_hp and solver query parameters are the same
+// as for GetLevelSolution, min_survival is not supported. At most max_events first events are returned.
+func GetLevelSolutionTrace(c echo.Context) error {
+	position, code, Err := getPosition(c.Param("id"))
+	if Err != nil {
+		return c.JSON(code, *Err)
+	}
+	p, err := bindGetLevelSolutionParams(c, position.Rules)
+	if err == nil && p.MinSurvival > 0 {
+		err = errors.New("min_survival is not supported by the trace")
+	}
+	var maxEvents int
+	if err == nil {
+		maxEvents, err = bindMaxEvents(c)
+	}
+	if err != nil {
+		return c.JSON(http.StatusUnprocessableEntity, game.Error{Code: service.ErrValidationRequest, Message: err.Error()})
+	}
+	if p.StartingHP > 0 {
+		position.Rules.StartingHP = p.StartingHP
+	}
+
+	trace := &game.SearchTrace{Events: []game.TraceEvent{}, MaxEvents: maxEvents}
+	path, Err := position.SolveTraced(p.Solver, trace)
+	r := GetLevelSolutionTraceResponse{Events: trace.Events, Truncated: trace.Truncated}
+	switch {
+	case Err == nil:
+		solution := solutionResponse(path)
+		r.Solution = &solution
+	case Err.Code != service.ErrNoSurvivablePath:
+		code = http.StatusUnprocessableEntity
+		return c.JSON(code, *Err)
+	}
+	return c.JSON(http.StatusOK, r)
+}
@@ -0,0 +1,126 @@
+package api
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/labstack/echo/v4"
+	"github.com/mtfelian/gjg-test-task/game"
+	"github.com/mtfelian/gjg-test-task/service"
+	"github.com/mtfelian/gjg-test-task/storage/model"
+)
+
+// levels page size limits
+const (
+	DefaultLevelsLimit = 20
+	MaxLevelsLimit     = 100
+)
+
+// GetLevelsResponse represents response for GetLevels handler
+type GetLevelsResponse struct {
+	Levels []GetLevelResponse `json:"levels"`
+	Next   string             `json:"next,omitempty"` // link to the next page, empty on the last page
+}
+
+// encodeCursor c to the string to be passed in the query
+func encodeCursor(c *model.LevelsCursor) (string, error) {
+	b, err := json.Marshal(c)
+	if err != nil {
+		return "", err
+	}
+	return base64.RawURLEncoding.EncodeToString(b), nil
+}
+
+// decodeCursor from the string s passed in the query
+func decodeCursor(s string) (*model.LevelsCursor, error) {
+	b, err := base64.RawURLEncoding.DecodeString(s)
+	if err != nil {
+		return nil, err
+	}
+	c := new(model.LevelsCursor)
+	if err = json.Unmarshal(b, c); err != nil {
+		return nil, err
+	}
+	return c, nil
+}
+
+// bindGetLevelsParams from the query of the request c
+func bindGetLevelsParams(c echo.Context) (p model.GetLevelsParams, err error) {
+	var order, cursor string
+	p.Limit = DefaultLevelsLimit
+	if err = echo.QueryParamsBinder(c).
+		Int("min_x", &p.MinX).
+		Int("max_x", &p.MaxX).
+		Int("min_y", &p.MinY).
+		Int("max_y", &p.MaxY).
+		Int("min_score", &p.MinScore).
+		Int("max_score", &p.MaxScore).
+		String("author", &p.Author).
+		Time("created_after", &p.CreatedAfter, time.RFC3339).
+		Time("created_before", &p.CreatedBefore, time.RFC3339).
+		String("sort", &p.SortBy).
+		String("order", &order).
+		Int("limit", &p.Limit).
+		String("cursor", &cursor).
+		BindError(); err != nil {
+		return
+	}
+
+	switch {
+	case p.SortBy != "" && !model.IsValidSortField(p.SortBy):
+		return p, fmt.Errorf("invalid sort field: %s", p.SortBy)
+	case order != "" && order != "asc" && order != "desc":
+		return p, fmt.Errorf("order should be asc or desc, got: %s", order)
+	case p.Limit < 1 || p.Limit > MaxLevelsLimit:
+		return p, fmt.Errorf("limit should be from 1 to %d, got: %d", MaxLevelsLimit, p.Limit)
+	}
+	p.Descending = order == "desc"
+
+	if cursor != "" {
+		if p.After, err = decodeCursor(cursor); err != nil {
+			return p, fmt.Errorf("invalid cursor: %v", err)
+		}
+	}
+	return
+}
+
+// GetLevels is an API handler to list stored levels with filtering, sorting and cursor-based pagination
+func GetLevels(c echo.Context) error {
+	var code int
+	p, err := bindGetLevelsParams(c)
+	if err != nil {
+		code = http.StatusUnprocessableEntity
+		return c.JSON(code, game.Error{Code: service.ErrValidationRequest, Message: err.Error()})
+	}
+
+	// request one more level to find out whether the next page exists
+	limit := p.Limit
+	p.Limit++
+	levels, err := service.Get().Storage.GetLevels(p)
+	if err != nil {
+		code = http.StatusInternalServerError
+		return c.JSON(code, game.Error{Code: service.ErrStorageFailed, Message: err.Error()})
+	}
+
+	var r GetLevelsResponse
+	if len(levels) > limit {
+		levels = levels[:limit]
+		cursor, err := encodeCursor(model.NewLevelsCursor(levels[limit-1]))
+		if err != nil {
+			code = http.StatusInternalServerError
+			return c.JSON(code, game.Error{Code: service.ErrStorageFailed, Message: err.Error()})
+		}
+		query := c.QueryParams()
+		query.Set("cursor", cursor)
+		r.Next = c.Request().URL.Path + "?" + query.Encode()
+	}
+
+	r.Levels = make([]GetLevelResponse, len(levels))
+	for i, level := range levels {
+		r.Levels[i] = levelResponse(level)
+	}
+	return c.JSON(http.StatusOK, r)
+}
@@ -0,0 +1,109 @@
+package api
+
+import (
+	"encoding/json"
+	"fmt"
+	"math"
+	"net/http"
+
+	"github.com/go-openapi/strfmt"
+	"github.com/mtfelian/gjg-test-task/game"
+	"github.com/mtfelian/gjg-test-task/service"
+	"github.com/mtfelian/gjg-test-task/storage"
+	"github.com/mtfelian/gjg-test-task/storage/model"
+)
+
+// Maze is a 2D level array, it is marshaled to JSON as an array of arrays of numbers
+type Maze [][]byte
+
+// MarshalJSON makes Maze to implement json.Marshaler
+func (m Maze) MarshalJSON() ([]byte, error) {
+	rows := make([][]int, len(m))
+	for i, row := range m {
+		rows[i] = make([]int, len(row))
+		for j, cell := range row {
+			rows[i][j] = int(cell)
+		}
+	}
+	return json.Marshal(rows)
+}
+
+// UnmarshalJSON makes Maze to implement json.Unmarshaler.
+// It returns game.Errors naming the cells which values do not fit into a byte. The maze is set in this case too,
+// with such cells left zero, to allow the rest of validation to be performed.
+func (m *Maze) UnmarshalJSON(b []byte) error {
+	var rows [][]json.Number
+	if err := json.Unmarshal(b, &rows); err != nil {
+		return err
+	}
+	var errs game.Errors
+	maze := make(Maze, len(rows))
+	for i, row := range rows {
+		maze[i] = make([]byte, len(row))
+		for j, cell := range row {
+			value, err := cell.Int64()
+			if err != nil {
+				return fmt.Errorf("row %d column %d contains non-integer value %s", i, j, cell)
+			}
+			if value < 0 || value > math.MaxUint8 {
+				errs = append(errs, game.Error{
+					Code:    service.ErrValidationCellIsOutOfRange,
+					Message: fmt.Sprintf("Row %d column %d contains value %d out of range [0,%d]", i, j, value, math.MaxUint8),
+					Params:  []interface{}{i, j, value, math.MaxUint8},
+					Cell:    &game.JI{J: j, I: i},
+				})
+				continue
+			}
+			maze[i][j] = byte(value)
+		}
+	}
+	*m = maze
+	if len(errs) > 0 {
+		return errs
+	}
+	return nil
+}
+
+// getLevel loads the level with the given id from the storage.
+// On failure it returns HTTP status code and an error to respond with.
+func getLevel(id string) (*model.Level, int, *game.Error) {
+	if !strfmt.IsUUID(id) {
+		return nil, http.StatusUnprocessableEntity, &game.Error{
+			Code:    service.ErrValidationRequest,
+			Message: "Level ID should be UUID, got: " + id,
+			Params:  []interface{}{id},
+		}
+	}
+
+	level, err := service.Get().Storage.GetLevel(strfmt.UUID(id))
+	switch {
+	case err == storage.ErrLevelNotFound:
+		return nil, http.StatusNotFound, &game.Error{
+			Code:    service.ErrLevelNotFound,
+			Message: "Level not found: " + id,
+			Params:  []interface{}{id},
+		}
+	case err != nil:
+		return nil, http.StatusInternalServerError, &game.Error{Code: service.ErrStorageFailed, Message: err.Error()}
+	}
+	return level, http.StatusOK, nil
+}
+
+// getPosition loads the level with the given id from the storage and converts it to the position
+// with the configured game rules. On failure it returns HTTP status code and an error to respond with.
+func getPosition(id string) (*game.Position, int, *game.Error) {
+	level, code, Err := getLevel(id)
+	if Err != nil {
+		return nil, code, Err
+	}
+
+	gameRules, err := GameRules(service.Get().Conf)
+	if err != nil {
+		return nil, http.StatusInternalServerError,
+			&game.Error{Code: service.ErrConfigurationInvalid, Message: err.Error()}
+	}
+
+	position := game.FromStorage(*level)
+	position.Rules = gameRules
+	return &position, http.StatusOK, nil
+}
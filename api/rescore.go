@@ -0,0 +1,64 @@
+package api
+
+import (
+	"github.com/mtfelian/gjg-test-task/game"
+	"github.com/mtfelian/gjg-test-task/service"
+	"github.com/mtfelian/gjg-test-task/storage/model"
+)
+
+// rescorePageSize is a number of levels loaded from the storage at once by RescoreLevels
+const rescorePageSize = 100
+
+// scoreLevel sets the minimum survivable path data of the level according to the position it is made from.
+// The level is left unsolvable if there is no survivable path, an error is returned only if the solver fails
+// or exceeds the state budget.
+func scoreLevel(level *model.Level, position *game.Position) *game.Error {
+	level.LengthScore, level.MoveCost, level.Damage, level.Solvable = 0, 0, 0, false
+	path, Err := position.SolveAStar()
+	switch {
+	case Err == nil:
+		level.LengthScore, level.MoveCost, level.Damage, level.Solvable = path.Length, path.Cost, path.Damage, true
+	case Err.Code == service.ErrSolverFailed, Err.Code == service.ErrSolverBudgetExceeded:
+		return Err
+	}
+	return nil
+}
+
+// RescoreLevels recalculates the minimum survivable path data of all stored levels with the configured game rules
+// and returns the number of levels updated. It backfills scores of levels stored before they were calculated
+// on submit and should be run after migrations changing the scoring. Levels exceeding the state budget
+// of the rules keep their stored scores.
+func RescoreLevels() (count int, err error) {
+	s := service.Get()
+	gameRules, err := GameRules(s.Conf)
+	if err != nil {
+		return 0, err
+	}
+	p := model.GetLevelsParams{Limit: rescorePageSize}
+	for {
+		levels, err := s.Storage.GetLevels(p)
+		if err != nil {
+			return count, err
+		}
+		for _, level := range levels {
+			position := game.FromStorage(level)
+			position.Rules = gameRules
+			Err := scoreLevel(&level, &position)
+			switch {
+			case Err != nil && Err.Code == service.ErrSolverBudgetExceeded:
+				s.Logger.Warnf("Level %s is not rescored: %s", level.ID, Err.Message)
+				continue
+			case Err != nil:
+				return count, Err
+			}
+			if err = s.Storage.UpdateLevelScore(level); err != nil {
+				return count, err
+			}
+			count++
+		}
+		if len(levels) < rescorePageSize {
+			return count, nil
+		}
+		p.After = model.NewLevelsCursor(levels[len(levels)-1])
+	}
+}
@@ -0,0 +1,47 @@
+package api
+
+import (
+	"fmt"
+
+	"github.com/mtfelian/gjg-test-task/config"
+	"github.com/mtfelian/gjg-test-task/game"
+	"github.com/spf13/viper"
+)
+
+// GameRules returns the game rules according to the configuration conf
+func GameRules(conf *viper.Viper) (*game.Rules, error) {
+	rules := game.DefaultRules()
+	if conf.IsSet(config.Palette) {
+		var tiles []game.Tile
+		if err := conf.UnmarshalKey(config.Palette, &tiles); err != nil {
+			return nil, err
+		}
+		palette, err := game.NewPalette(tiles)
+		if err != nil {
+			return nil, err
+		}
+		rules.Palette = palette
+	}
+	if hp := conf.GetInt(config.StartingHP); hp > 0 {
+		rules.StartingHP = hp
+	}
+	if cost := conf.GetInt(config.TeleportCost); cost > 0 {
+		rules.TeleportCost = cost
+	}
+	if maxDim := conf.GetInt(config.MaxDim); maxDim > 0 {
+		rules.MaxDim = maxDim
+	}
+	if maxStates := conf.GetInt(config.MaxStates); maxStates > 0 {
+		rules.MaxStates = maxStates
+	}
+	if maxLintCells := conf.GetInt(config.MaxLintCells); maxLintCells > 0 {
+		rules.MaxLintCells = maxLintCells
+	}
+	if mode := conf.GetString(config.ExitMode); mode != "" {
+		if !game.IsValidExitMode(mode) {
+			return nil, fmt.Errorf("unknown exit mode: %s", mode)
+		}
+		rules.ExitMode = mode
+	}
+	return rules, nil
+}
@@ -1,56 +1,137 @@
 package api
 
 import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"io/ioutil"
 	"net/http"
 
 	"github.com/go-openapi/strfmt"
 	"github.com/labstack/echo/v4"
+	"github.com/mtfelian/gjg-test-task/config"
 	"github.com/mtfelian/gjg-test-task/game"
 	"github.com/mtfelian/gjg-test-task/service"
 )
 
 // SubmitLevelParams represents parameters for SubmitLevel handler
 type SubmitLevelParams struct {
-	Maze [][]byte `json:"maze"`
+	Maze          Maze                `json:"maze"`
+	Author        string              `json:"author"`
+	Teleporters   []game.Teleporter   `json:"teleporters"` // pairs of teleporter cells
+	PeriodicTraps []game.PeriodicTrap `json:"periodic_traps"`
+	Enemies       []game.Enemy        `json:"enemies"`
+	Topology      string              `json:"topology"` // movement topology, empty means 4way
 }
 
 // SubmitLevelResponse represents response for SubmitLevel handler
 type SubmitLevelResponse struct {
-	LevelID strfmt.UUID `json:"id"`
+	LevelID     strfmt.UUID `json:"id"`
+	LengthScore int         `json:"length_score"` // number of moves of the minimum survivable path
+	MoveCost    int         `json:"move_cost"`    // total move cost of the minimum survivable path in ticks
+	Damage      int         `json:"damage"`
+	Solvable    bool        `json:"solvable"`
+	Warnings    game.Errors `json:"warnings,omitempty"` // non-fatal lint warnings
 }
 
-// Model converts API model to storage layer model
-func (p SubmitLevelParams) ToPosition() (*game.Position, *game.Error) {
-	position := &game.Position{Maze: p.Maze}
-	if err := position.Validate(); err != nil {
-		return nil, err
+// ValidationErrorResponse represents response for the level failed validation.
+// It contains the first violation on the top level and all of them in Errors field.
+type ValidationErrorResponse struct {
+	game.Error
+	Errors game.Errors `json:"errors"`
+}
+
+// newValidationErrorResponse returns a response for the given non-empty list of violations
+func newValidationErrorResponse(errs game.Errors) ValidationErrorResponse {
+	return ValidationErrorResponse{Error: errs[0], Errors: errs}
+}
+
+// Model converts API model to storage layer model.
+// The position is validated according to the game rules gameRules and additional validation rules.
+func (p SubmitLevelParams) ToPosition(gameRules *game.Rules, rules ...game.Validator) (*game.Position, game.Errors) {
+	position := &game.Position{
+		Maze:          p.Maze,
+		Teleporters:   p.Teleporters,
+		PeriodicTraps: p.PeriodicTraps,
+		Enemies:       p.Enemies,
+		Topology:      p.Topology,
+		Rules:         gameRules,
+	}
+	if position.Topology == "" {
+		position.Topology = game.Topology4Way
+	}
+	if errs := position.ValidateAll(rules...); len(errs) > 0 {
+		return nil, errs
 	}
 	position.X = len(p.Maze[0])
 	position.Y = len(p.Maze)
 	return position, nil
 }
 
+// bindSubmitLevelParams from the request body. The level may be given either as an object with the maze field
+// or as a bare array of arrays described in the README, the format is chosen by the first JSON token.
+func bindSubmitLevelParams(c echo.Context) (*SubmitLevelParams, error) {
+	body, err := ioutil.ReadAll(c.Request().Body)
+	if err != nil {
+		return nil, err
+	}
+	p := new(SubmitLevelParams)
+	if bytes.HasPrefix(bytes.TrimSpace(body), []byte("[")) {
+		return p, json.Unmarshal(body, &p.Maze)
+	}
+	return p, json.Unmarshal(body, p)
+}
+
 // SubmitLevel is an API handler to submit level
 func SubmitLevel(c echo.Context) error {
 	var code int
-	p := new(SubmitLevelParams)
-	if err := c.Bind(p); err != nil {
+	var errs game.Errors
+	p, err := bindSubmitLevelParams(c)
+	if err != nil && !errors.As(err, &errs) {
 		code = http.StatusUnprocessableEntity
 		return c.JSON(code, game.Error{Code: service.ErrValidationRequest, Message: err.Error()})
 	}
 
-	position, Err := p.ToPosition()
-	if Err != nil {
+	s := service.Get()
+	gameRules, err := GameRules(s.Conf)
+	if err != nil {
+		code = http.StatusInternalServerError
+		return c.JSON(code, game.Error{Code: service.ErrConfigurationInvalid, Message: err.Error()})
+	}
+	rules, err := game.ValidatorsByNames(s.Conf.GetStringSlice(config.ValidationRules))
+	if err != nil {
+		code = http.StatusInternalServerError
+		return c.JSON(code, game.Error{Code: service.ErrConfigurationInvalid, Message: err.Error()})
+	}
+
+	position, validationErrs := p.ToPosition(gameRules, rules...)
+	if errs = append(errs, validationErrs...); len(errs) > 0 {
 		code = http.StatusBadRequest
+		return c.JSON(code, newValidationErrorResponse(errs))
+	}
+
+	level := position.ToStorage()
+	level.Author = p.Author
+	if Err := scoreLevel(&level, position); Err != nil {
+		code = http.StatusInternalServerError
+		if Err.Code == service.ErrSolverBudgetExceeded {
+			code = http.StatusUnprocessableEntity
+		}
 		return c.JSON(code, *Err)
 	}
 
-	s := service.Get()
-	newLevelID, err := s.Storage.AddLevel(position.ToStorage())
+	newLevelID, err := s.Storage.AddLevel(level)
 	if err != nil {
 		code = http.StatusInternalServerError
 		return c.JSON(code, game.Error{Code: service.ErrStorageFailed, Message: err.Error()})
 	}
 
-	return c.JSON(http.StatusCreated, SubmitLevelResponse{LevelID: newLevelID})
+	return c.JSON(http.StatusCreated, SubmitLevelResponse{
+		LevelID:     newLevelID,
+		LengthScore: level.LengthScore,
+		MoveCost:    level.MoveCost,
+		Damage:      level.Damage,
+		Solvable:    level.Solvable,
+		Warnings:    position.Lint(),
+	})
 }
@@ -27,8 +27,18 @@ const (
 	DBLogin    = "db_login"
 	DBPassword = "db_password"
 	DBMigrate  = "db_migrate"
+	Rescore    = "rescore"
 
 	LogLevel = "loglevel"
+
+	ValidationRules = "validation_rules"
+	Palette         = "palette"
+	StartingHP      = "starting_hp"
+	TeleportCost    = "teleport_cost"
+	ExitMode        = "exit_mode"
+	MaxDim          = "max_dim"
+	MaxStates       = "max_states"
+	MaxLintCells    = "max_lint_cells"
 )
 
 // errors
@@ -70,6 +80,16 @@ func parseFlags() error {
 	pflag.StringVar(&params.DBLogin, DBLogin, "postgres", "DB login")
 	pflag.StringVar(&params.DBPassword, DBPassword, "", "DB password")
 	pflag.StringVar(&params.DBMigrate, DBMigrate, "", "DB migration commands")
+	pflag.BoolVar(&params.Rescore, Rescore, false, "recalculate scores of all stored levels and exit")
+
+	pflag.StringSliceVar(&params.ValidationRules, ValidationRules, nil, "additional level validation rules")
+	pflag.IntVar(&params.StartingHP, StartingHP, 0, "player starting HP, 0 means default")
+	pflag.IntVar(&params.TeleportCost, TeleportCost, 0, "move cost of the teleportation, 0 means default")
+	pflag.StringVar(&params.ExitMode, ExitMode, "", "what counts as an exit: tile, border or both, empty means both")
+	pflag.IntVar(&params.MaxDim, MaxDim, 0, "max number of rows and columns of a level, 0 means default")
+	pflag.IntVar(&params.MaxStates, MaxStates, 0, "max number of search states of a level, 0 means default")
+	pflag.IntVar(&params.MaxLintCells, MaxLintCells, 0,
+		"max number of cells of a level to check survivable paths by lint, 0 means default")
 
 	pflag.Parse()
 	return viper.BindPFlags(pflag.CommandLine)
@@ -115,9 +135,26 @@ type Options struct {
 	DBPassword string
 	// DBMigrate is DB migration tool commands
 	DBMigrate string
+	// Rescore is whether to recalculate scores of all stored levels and exit
+	Rescore bool
 
 	// LogLevel is a logging level
 	LogLevel string
+
+	// ValidationRules are names of additional level validation rules
+	ValidationRules []string
+	// StartingHP is a player starting HP
+	StartingHP int
+	// TeleportCost is a move cost of the teleportation
+	TeleportCost int
+	// ExitMode is what counts as an exit
+	ExitMode string
+	// MaxDim is a max number of rows and columns of a level
+	MaxDim int
+	// MaxStates is a max number of search states of a level
+	MaxStates int
+	// MaxLintCells is a max number of cells of a level to check survivable paths by lint
+	MaxLintCells int
 }
 
 // params is an application command line parameters
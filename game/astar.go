@@ -0,0 +1,80 @@
+package game
+
+// solvers of the minimum survivable path
+const (
+	SolverBFS   = "bfs"   // expands states in order of their move cost, as the breadth-first search does for unit costs
+	SolverAStar = "astar" // expands states in order of their move cost plus the estimate of the rest of the way
+)
+
+// IsValidSolver returns true if solver is a known solver of the minimum survivable path
+func IsValidSolver(solver string) bool {
+	return solver == SolverBFS || solver == SolverAStar
+}
+
+// Heuristic estimates the move cost from the cell to the nearest exit. To keep the found paths minimum
+// it should never exceed the real cost and should not decrease by more than the cost of any move.
+type Heuristic func(cell JI) int
+
+// AStarSurvivablePath searches for the minimum survivable path in the grid g from start to the nearest of exits
+// as MinSurvivablePath does, but the search is guided by the estimate h of the move cost to the nearest exit.
+// States far from exits are not expanded, so paths are found faster in large mazes.
+func AStarSurvivablePath(g *Grid, start JI, exits []JI, h Heuristic) (*Path, error) {
+	return minSurvivablePath(g, start, exits, h)
+}
+
+// AStarLikelySurvivablePath searches for the minimum path in the grid g from start to the nearest of exits
+// which is survived with the probability not less than minChance as MinLikelySurvivablePath does,
+// but the search is guided by the estimate h of the move cost to the nearest exit.
+func AStarLikelySurvivablePath(g *Grid, start JI, exits []JI, minChance float64, h Heuristic) (*Path, error) {
+	return minLikelySurvivablePath(g, start, exits, minChance, h)
+}
+
+// heuristic returns the estimate of the move cost from a cell to the nearest of exits. It is the number of moves
+// to the nearest exit in the maze without walls, which is the Manhattan distance for 4way topology, multiplied
+// by the least move cost of a tile of the maze. Teleportations may be cheaper, so there is no estimate
+// for the position with teleporters.
+func (p Position) heuristic(exits []JI) Heuristic {
+	if len(p.Teleporters) > 0 || len(p.Maze) == 0 {
+		return nil
+	}
+	palette := p.rules().Palette
+	minCost := 0
+	for _, row := range p.Maze {
+		for _, cell := range row {
+			if cost := palette.Cost(cell); palette.Passable(cell) && (minCost == 0 || cost < minCost) {
+				minCost = cost
+			}
+		}
+	}
+
+	// multi-source breadth-first search from exits over all cells, adjacency of cells is symmetric in all topologies
+	width := len(p.Maze[0])
+	moves := make([]int, len(p.Maze)*width)
+	for k := range moves {
+		moves[k] = -1
+	}
+	queue := make([]JI, 0, len(exits))
+	for _, exit := range exits {
+		moves[exit.I*width+exit.J] = 0
+		queue = append(queue, exit)
+	}
+	for len(queue) > 0 {
+		current := queue[0]
+		queue = queue[1:]
+		for _, d := range p.moves(current.I) {
+			n, ok := p.neighbour(current, d)
+			if !ok || moves[n.I*width+n.J] >= 0 {
+				continue
+			}
+			moves[n.I*width+n.J] = moves[current.I*width+current.J] + 1
+			queue = append(queue, n)
+		}
+	}
+
+	return func(cell JI) int {
+		if m := moves[cell.I*width+cell.J]; m > 0 {
+			return m * minCost
+		}
+		return 0
+	}
+}
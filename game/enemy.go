@@ -0,0 +1,114 @@
+package game
+
+import (
+	"fmt"
+
+	"github.com/mtfelian/gjg-test-task/service"
+	"github.com/mtfelian/gjg-test-task/storage/model"
+)
+
+// Enemy patrols the level along the route, going back to its first cell after the last one.
+// The player takes the damage on each tick the enemy and the player are at the same cell.
+type Enemy struct {
+	Route  []JI `json:"route"`
+	Rate   int  `json:"rate"` // number of ticks the enemy stays at each cell of the route
+	Damage int  `json:"damage"`
+}
+
+// period returns the number of ticks after which the enemy repeats its route
+func (e Enemy) period() int { return len(e.Route) * e.Rate }
+
+// at returns the cell the enemy is at on the given tick
+func (e Enemy) at(tick int) JI { return e.Route[tick/e.Rate%len(e.Route)] }
+
+// EnemyHit describes the player taking the contact damage from an enemy on the path
+type EnemyHit struct {
+	Enemy  int `json:"enemy"` // index of the enemy in the level
+	Cell   JI  `json:"cell"`
+	Tick   int `json:"tick"`
+	Damage int `json:"damage"`
+}
+
+// enemyPeriods returns periods of the given enemies
+func enemyPeriods(enemies []Enemy) []int {
+	res := make([]int, len(enemies))
+	for k, e := range enemies {
+		res[k] = e.period()
+	}
+	return res
+}
+
+// enemiesToStorage converts enemies to the storage layer format
+func enemiesToStorage(enemies []Enemy) []model.Enemy {
+	res := make([]model.Enemy, len(enemies))
+	for k, e := range enemies {
+		res[k] = model.Enemy{Route: make([]model.Point, len(e.Route)), Rate: e.Rate, Damage: e.Damage}
+		for n, c := range e.Route {
+			res[k].Route[n] = model.Point{X: c.J, Y: c.I}
+		}
+	}
+	return res
+}
+
+// enemiesFromStorage converts enemies from the storage layer format
+func enemiesFromStorage(enemies []model.Enemy) []Enemy {
+	if len(enemies) == 0 {
+		return nil
+	}
+	res := make([]Enemy, len(enemies))
+	for k, e := range enemies {
+		res[k] = Enemy{Route: make([]JI, len(e.Route)), Rate: e.Rate, Damage: e.Damage}
+		for n, c := range e.Route {
+			res[k].Route[n] = JI{c.X, c.Y}
+		}
+	}
+	return res
+}
+
+// validateEnemies checks that enemies of the structurally valid position p patrol passable cells
+// moving to an adjacent cell at a time and repeat their routes together with periodic traps often enough
+func validateEnemies(p Position) (errs Errors) {
+	palette := p.rules().Palette
+	invalid := func(k int, reason string) Error {
+		return Error{
+			Code:    service.ErrValidationEnemyIsInvalid,
+			Message: fmt.Sprintf("Enemy %d %s", k, reason),
+			Params:  []interface{}{k},
+		}
+	}
+
+	for k, e := range p.Enemies {
+		if len(e.Route) == 0 || e.Rate < 1 || e.Damage < 0 {
+			errs = append(errs, invalid(k, fmt.Sprintf("has empty route, invalid rate %d or negative damage %d",
+				e.Rate, e.Damage)))
+			continue
+		}
+		for n, c := range e.Route {
+			if c.I < 0 || c.I >= len(p.Maze) || c.J < 0 || c.J >= len(p.Maze[c.I]) || !palette.Passable(p.Maze[c.I][c.J]) {
+				err := invalid(k, fmt.Sprintf("route cell (%d,%d) is not passable", c.I, c.J))
+				err.Cell = &JI{c.J, c.I}
+				errs = append(errs, err)
+				continue
+			}
+			next := e.Route[(n+1)%len(e.Route)]
+			if next != c && !p.adjacent(c, next) {
+				err := invalid(k, fmt.Sprintf("route cell (%d,%d) is not adjacent to the next one", c.I, c.J))
+				err.Cell = &JI{c.J, c.I}
+				errs = append(errs, err)
+			}
+		}
+	}
+	if len(errs) > 0 || len(p.Enemies) == 0 {
+		return
+	}
+
+	periods := append(trapPeriods(p.PeriodicTraps), enemyPeriods(p.Enemies)...)
+	if cycle := cycleOf(periods); cycle > maxCycle {
+		errs = append(errs, Error{
+			Code:    service.ErrValidationEnemyIsInvalid,
+			Message: fmt.Sprintf("Enemies and periodic traps repeat every %d ticks, max is %d", cycle, maxCycle),
+			Params:  []interface{}{cycle, maxCycle},
+		})
+	}
+	return
+}
@@ -1,13 +1,29 @@
 package game
 
-import "fmt"
+import (
+	"fmt"
+	"strings"
+)
 
 // Error describes API error response
 type Error struct {
-	Code    int           `json:"code"`    // error code
-	Message string        `json:"message"` // default message with params substituted
-	Params  []interface{} `json:"params"`  // params for i18n localized messages
+	Code    int           `json:"code"`           // error code
+	Message string        `json:"message"`        // default message with params substituted
+	Params  []interface{} `json:"params"`         // params for i18n localized messages
+	Cell    *JI           `json:"cell,omitempty"` // coordinates of the cell the error relates to, if any
 }
 
 // Error makes Error to implement error
 func (e Error) Error() string { return fmt.Sprintf("%d: %s", e.Code, e.Message) }
+
+// Errors is a list of errors, e.g. all violations found by validation
+type Errors []Error
+
+// Error makes Errors to implement error
+func (e Errors) Error() string {
+	messages := make([]string, len(e))
+	for i, err := range e {
+		messages[i] = err.Error()
+	}
+	return strings.Join(messages, "; ")
+}
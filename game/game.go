@@ -14,27 +14,60 @@ const (
 	CellPit
 	CellArrow
 	CellPlayer
+	CellPotion
+	CellKeyRed
+	CellKeyGreen
+	CellKeyBlue
+	CellDoorRed
+	CellDoorGreen
+	CellDoorBlue
+	CellTeleporter
+	CellConveyorUp
+	CellConveyorDown
+	CellConveyorLeft
+	CellConveyorRight
+	CellRandomTrap
+	CellExit
+	CellMud
+	CellWater
 )
 
 // dimension limits
 const (
-	MaxDim     = 100
-	MinDim     = 2
-	startingHP = 4
+	MaxDim       = 100 // default one, see Rules
+	MinDim       = 2
+	MaxLintCells = MaxDim * MaxDim // default one, see Rules
+	startingHP   = 4
+	teleportCost = 1
 )
 
 // Position represents game field
 type Position struct {
-	Maze [][]byte
-	X, Y int // to be set after validation
+	Maze          [][]byte
+	X, Y          int // to be set after validation
+	Teleporters   []Teleporter
+	PeriodicTraps []PeriodicTrap
+	Enemies       []Enemy
+	Topology      string // movement topology, empty means 4way
+	Rules         *Rules // nil means default rules
 }
 
 // ToStorage converts position p to storage layer format
 func (p Position) ToStorage() model.Level {
 	level := model.Level{
-		X:    p.X,
-		Y:    p.Y,
-		Maze: make([]byte, p.X*p.Y),
+		X:        p.X,
+		Y:        p.Y,
+		Maze:     make([]byte, p.X*p.Y),
+		Topology: p.Topology,
+	}
+	if len(p.Teleporters) > 0 {
+		level.Teleporters = teleportersToStorage(p.Teleporters)
+	}
+	if len(p.PeriodicTraps) > 0 {
+		level.PeriodicTraps = periodicTrapsToStorage(p.PeriodicTraps)
+	}
+	if len(p.Enemies) > 0 {
+		level.Enemies = enemiesToStorage(p.Enemies)
 	}
 	for i, row := range p.Maze {
 		for j, cell := range row {
@@ -44,100 +77,313 @@ func (p Position) ToStorage() model.Level {
 	return level
 }
 
-// Validate the field
-func (p Position) Validate() *Error {
+// FromStorage converts a level from the storage layer format to a position
+func FromStorage(level model.Level) Position {
+	p := Position{
+		X:             level.X,
+		Y:             level.Y,
+		Maze:          make([][]byte, level.Y),
+		Teleporters:   teleportersFromStorage(level.Teleporters),
+		PeriodicTraps: periodicTrapsFromStorage(level.PeriodicTraps),
+		Enemies:       enemiesFromStorage(level.Enemies),
+		Topology:      level.Topology,
+	}
+	for i := range p.Maze {
+		p.Maze[i] = append([]byte(nil), level.Maze[i*level.X:(i+1)*level.X]...)
+	}
+	return p
+}
+
+// Start returns coordinates of the player starting position, ok is false if there is no one
+func (p Position) Start() (start JI, ok bool) {
+	for i, row := range p.Maze {
+		for j, cell := range row {
+			if cell == CellPlayer {
+				return JI{j, i}, true
+			}
+		}
+	}
+	return JI{}, false
+}
+
+// isExit returns true if the cell (i,j) is an exit according to the exit mode of the rules
+func (p Position) isExit(i, j int) bool {
+	rules := p.rules()
+	cell := p.Maze[i][j]
+	// the torus has no border, its opposite edges are adjacent
+	onBorder := p.topology() != TopologyTorus && (i == 0 || i == len(p.Maze)-1 || j == 0 || j == len(p.Maze[i])-1)
+	isBorderExit := onBorder && cell == CellOpen
+	isTileExit := rules.Palette[cell].Exit
+	switch rules.ExitMode {
+	case ExitModeBorder:
+		return isBorderExit
+	case ExitModeTile:
+		return isTileExit
+	}
+	return isBorderExit || isTileExit
+}
+
+// Exits returns coordinates of all exits of the maze. Depending on the exit mode of the rules exits are
+// exit tiles, open cells on the border of the maze or both. Only exit tiles are exits on the torus.
+func (p Position) Exits() (exits []JI) {
+	for i, row := range p.Maze {
+		for j := range row {
+			if p.isExit(i, j) {
+				exits = append(exits, JI{j, i})
+			}
+		}
+	}
+	return
+}
+
+// Validate the field with the given additional rules, returns the first violation found
+func (p Position) Validate(rules ...Validator) *Error {
+	if errs := p.ValidateAll(rules...); len(errs) > 0 {
+		return &errs[0]
+	}
+	return nil
+}
+
+// ValidateAll validates the field and returns all violations found.
+// Additional rules are checked only if the field is structurally valid.
+func (p Position) ValidateAll(rules ...Validator) (errs Errors) {
+	maxDim := p.rules().maxDim()
 	lenMaze := len(p.Maze)
 	switch {
-	case lenMaze > 100:
-		return &Error{
+	case lenMaze > maxDim:
+		errs = append(errs, Error{
 			Code:    service.ErrValidationFieldIsTooLarge,
-			Message: fmt.Sprintf("Position contains %d rows, max is %d", lenMaze, MaxDim),
-			Params:  []interface{}{lenMaze, MaxDim},
-		}
-	case lenMaze < 2:
-		return &Error{
+			Message: fmt.Sprintf("Position contains %d rows, max is %d", lenMaze, maxDim),
+			Params:  []interface{}{lenMaze, maxDim},
+		})
+	case lenMaze < MinDim:
+		errs = append(errs, Error{
 			Code:    service.ErrValidationFieldIsTooSmall,
 			Message: fmt.Sprintf("Position contains %d rows, min is %d", lenMaze, MinDim),
 			Params:  []interface{}{lenMaze, MinDim},
-		}
+		})
+	}
+	if lenMaze == 0 {
+		return
 	}
 
+	palette := p.rules().Palette
 	row0Length := len(p.Maze[0])
 	for i, row := range p.Maze {
 		lenRow := len(row)
 		switch {
 		case lenRow != row0Length:
-			return &Error{
+			errs = append(errs, Error{
 				Code:    service.ErrValidationFieldIsNotRectangular,
 				Message: fmt.Sprintf("Row %d contains %d columns, while row 0 contains %d", i, lenRow, row0Length),
 				Params:  []interface{}{i, lenRow, row0Length},
-			}
-		case lenRow > 100:
-			return &Error{
+			})
+		case lenRow > maxDim:
+			errs = append(errs, Error{
 				Code:    service.ErrValidationFieldIsTooLarge,
-				Message: fmt.Sprintf("Row %d contains %d columns, max is %d", i, lenRow, MaxDim),
-				Params:  []interface{}{i, lenRow, MaxDim},
-			}
-		case lenRow < 2:
-			return &Error{
+				Message: fmt.Sprintf("Row %d contains %d columns, max is %d", i, lenRow, maxDim),
+				Params:  []interface{}{i, lenRow, maxDim},
+			})
+		case lenRow < MinDim:
+			errs = append(errs, Error{
 				Code:    service.ErrValidationFieldIsTooSmall,
 				Message: fmt.Sprintf("Row %d contains %d columns, min is %d", i, lenRow, MinDim),
 				Params:  []interface{}{i, lenRow, MinDim},
-			}
+			})
 		}
 
 		for j, cell := range row {
-			if cell < CellOpen || cell > CellPlayer {
-				return &Error{
+			if !palette.Has(cell) {
+				errs = append(errs, Error{
 					Code:    service.ErrValidationFieldHasInvalidData,
 					Message: fmt.Sprintf("Cell (%d,%d) contains invalid value %d", i, j, cell),
-					Params:  []interface{}{i, lenRow, MinDim},
-				}
+					Params:  []interface{}{i, j, cell},
+					Cell:    &JI{j, i},
+				})
 			}
 		}
 	}
-	return nil
+	if errs = append(errs, validateTopology(p)...); len(errs) > 0 {
+		return
+	}
+	errs = append(validateTeleporters(p), validatePeriodicTraps(p)...)
+	if errs = append(errs, validateEnemies(p)...); len(errs) > 0 {
+		return
+	}
+	if errs = append(errs, validateStateSpace(p)...); len(errs) > 0 {
+		return
+	}
+
+	for _, rule := range rules {
+		errs = append(errs, rule.Validate(p)...)
+	}
+	return
 }
 
-// ToGraph converts a position to a graph
-func (p Position) ToGraph() (*Graph, error) {
-	res := NewGraph(startingHP)
+// ToGrid converts a rectangular position to a grid
+func (p Position) ToGrid() (*Grid, error) {
+	if !IsValidTopology(p.topology()) {
+		return nil, fmt.Errorf("unknown topology: %s", p.Topology)
+	}
+	height, width := len(p.Maze), 0
+	if height > 0 {
+		width = len(p.Maze[0])
+	}
+	rules := p.rules()
+	res := newGrid(width, height, make([]byte, width*height), rules)
 	for i, row := range p.Maze {
-		for j, cell := range row {
-			fmt.Println("adding vertex", j, i)
-			res.AddVertex(JI{j, i}, cell)
+		if len(row) != width {
+			return nil, fmt.Errorf("row %d contains %d columns, while row 0 contains %d", i, len(row), width)
 		}
+		copy(res.Cells[i*width:], row)
+	}
+
+	teleports := make(map[JI]Teleporter, len(p.Teleporters))
+	for _, t := range p.Teleporters {
+		teleports[t.A], teleports[t.B] = t, t
+		res.Teleports[t.A], res.Teleports[t.B] = t.B, t.A
 	}
+	// properties of tiles by their IDs, so the palette is not looked up for each move
+	var isPassable, isDirected [256]bool
+	var costs [256]int
+	for id, tile := range rules.Palette {
+		isPassable[id], isDirected[id], costs[id] = tile.Passable, tile.Direction != "", rules.Palette.Cost(id)
+	}
+	passable := func(c JI) bool { return isPassable[p.Maze[c.I][c.J]] }
 	for i, row := range p.Maze {
-		for j := range row {
-			if p.Maze[i][j] == CellWall {
-				continue
-			}
-			if j > 0 && p.Maze[i][j-1] != CellWall {
-				fmt.Println("adding edge", j, i, j-1, i)
-				if err := res.AddEdge(JI{j, i}, JI{j - 1, i}); err != nil {
-					return nil, err
-				}
-			}
-			if j < len(row)-1 && p.Maze[i][j+1] != CellWall {
-				fmt.Println("adding edge", j, i, j+1, i)
-				if err := res.AddEdge(JI{j, i}, JI{j + 1, i}); err != nil {
-					return nil, err
+		for j, cell := range row {
+			from := JI{j, i}
+			for _, d := range p.moves(i) {
+				to, ok := p.neighbour(from, d)
+				if !isPassable[cell] || !ok || !passable(to) || isDirected[cell] && !rules.Palette.CanLeave(cell, d) {
+					continue
 				}
-			}
-			if i > 0 && p.Maze[i-1][j] != CellWall {
-				fmt.Println("adding edge", j, i, j, i-1)
-				if err := res.AddEdge(JI{j, i}, JI{j, i - 1}); err != nil {
-					return nil, err
+				// diagonal move can not pass between two cells if any of them is impassable
+				if p.topology() == Topology8WayNoCorners && d.I != 0 && d.J != 0 &&
+					(!passable(JI{j + d.J, i}) || !passable(JI{j, i + d.I})) {
+					continue
 				}
+				res.addArc(res.Index(to), costs[p.Maze[to.I][to.J]])
 			}
-			if i < len(p.Maze)-1 && p.Maze[i+1][j] != CellWall {
-				fmt.Println("adding edge", j, i, j, i+1)
-				if err := res.AddEdge(JI{j, i}, JI{j, i + 1}); err != nil {
-					return nil, err
-				}
+			if t, ok := teleports[from]; ok {
+				res.addArc(res.Index(res.Teleports[from]), t.cost(rules))
 			}
+			res.endCell()
 		}
 	}
+	for _, t := range p.PeriodicTraps {
+		res.PeriodicTraps[t.Cell] = t
+	}
+	res.Enemies = p.Enemies
 	return res, nil
 }
+
+// Solve finds the minimum survivable path from the player starting position to the nearest exit
+func (p Position) Solve() (*Path, *Error) {
+	return p.solve(MinSurvivablePath)
+}
+
+// SolveWithChance finds the minimum path from the player starting position to the nearest exit
+// which is survived with the probability not less than minChance
+func (p Position) SolveWithChance(minChance float64) (*Path, *Error) {
+	return p.solve(func(g *Grid, start JI, exits []JI) (*Path, error) {
+		return MinLikelySurvivablePath(g, start, exits, minChance)
+	})
+}
+
+// SolveAStar finds the minimum survivable path from the player starting position to the nearest exit
+// with A* search, it is faster than Solve for large mazes
+func (p Position) SolveAStar() (*Path, *Error) {
+	return p.solve(func(g *Grid, start JI, exits []JI) (*Path, error) {
+		return AStarSurvivablePath(g, start, exits, p.heuristic(exits))
+	})
+}
+
+// SolveAStarWithChance finds the minimum path from the player starting position to the nearest exit
+// which is survived with the probability not less than minChance with A* search
+func (p Position) SolveAStarWithChance(minChance float64) (*Path, *Error) {
+	return p.solve(func(g *Grid, start JI, exits []JI) (*Path, error) {
+		return AStarLikelySurvivablePath(g, start, exits, minChance, p.heuristic(exits))
+	})
+}
+
+// SolveTraced finds the minimum survivable path as Solve or SolveAStar does depending on the solver,
+// events of the search are reported to tracer
+func (p Position) SolveTraced(solver string, tracer SearchTracer) (*Path, *Error) {
+	return p.solve(func(g *Grid, start JI, exits []JI) (*Path, error) {
+		g.Tracer = tracer
+		if solver == SolverAStar {
+			return AStarSurvivablePath(g, start, exits, p.heuristic(exits))
+		}
+		return MinSurvivablePath(g, start, exits)
+	})
+}
+
+// solve the position with the given search function
+func (p Position) solve(search func(g *Grid, start JI, exits []JI) (*Path, error)) (*Path, *Error) {
+	grid, start, exits, Err := p.searchParams()
+	if Err != nil {
+		return nil, Err
+	}
+	path, err := search(grid, start, exits)
+	if Err := searchError(err, start); Err != nil {
+		return nil, Err
+	}
+	return path, nil
+}
+
+// Routes finds all routes from the player starting position to exits which are not dominated
+// by another route on both move cost and damage taken. Routes are ordered by move cost.
+func (p Position) Routes() ([]*Path, *Error) {
+	grid, start, exits, Err := p.searchParams()
+	if Err != nil {
+		return nil, Err
+	}
+	paths, err := ParetoPaths(grid, start, exits)
+	if Err := searchError(err, start); Err != nil {
+		return nil, Err
+	}
+	return paths, nil
+}
+
+// searchParams returns the grid of the position, the player starting position and exits to search paths
+func (p Position) searchParams() (grid *Grid, start JI, exits []JI, Err *Error) {
+	start, ok := p.Start()
+	if !ok {
+		return nil, start, nil, &Error{
+			Code:    service.ErrLevelHasNoStart,
+			Message: "Position has no player starting position",
+		}
+	}
+	exits = p.Exits()
+	if len(exits) == 0 {
+		return nil, start, nil, &Error{
+			Code:    service.ErrLevelHasNoExit,
+			Message: "Position has no exits",
+		}
+	}
+
+	grid, err := p.ToGrid()
+	if err != nil {
+		return nil, start, nil, &Error{Code: service.ErrSolverFailed, Message: err.Error()}
+	}
+	return grid, start, exits, nil
+}
+
+// searchError converts the error of searching paths from start to the typed one, nil if err is nil.
+// The start is reported as (row,column) like cells in validation errors.
+func searchError(err error, start JI) *Error {
+	switch {
+	case err == nil:
+		return nil
+	case err == ErrNoSurvivablePath:
+		return &Error{
+			Code:    service.ErrNoSurvivablePath,
+			Message: fmt.Sprintf("There is no survivable path from (%d,%d) to any exit", start.I, start.J),
+			Params:  []interface{}{start.I, start.J},
+		}
+	case err == ErrStateBudgetExceeded:
+		return &Error{Code: service.ErrSolverBudgetExceeded, Message: "The search exceeded the state budget"}
+	}
+	return &Error{Code: service.ErrSolverFailed, Message: err.Error()}
+}
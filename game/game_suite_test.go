@@ -127,17 +127,14 @@ var _ = Describe("testing position", func() {
 						in: game.Position{
 							Maze: [][]byte{
 								{0, 1},
-								{0, 5},
+								{0, 255},
 								{0, 1},
 								{0, 4},
 							},
 						},
 						expectedCode: service.ErrValidationFieldHasInvalidData,
 					},
-					// additional validation ideas:
-					// - validate that there is only one "4"
-					// - validate that maze is separated by walls and there is only one "exit"
-					// - validate that there is a possible way to exit maze from starting position with survival conds
+					// additional validation rules are checked in validator_test.go
 				} {
 					Err := tc.in.Validate()
 					if tc.expectedCode == service.ErrOK {
@@ -148,6 +145,79 @@ var _ = Describe("testing position", func() {
 					Expect(Err.Code).To(Equal(tc.expectedCode), "case %d", i)
 				}
 			})
+			It("checks that all violations are collected", func() {
+				p := game.Position{
+					Maze: [][]byte{
+						{0, 1, 250},
+						{0, 0, 4, 1},
+						{251, 1, 0},
+						{0, 1},
+					},
+				}
+				errs := p.ValidateAll()
+				Expect(errs).To(HaveLen(4))
+				type expected struct {
+					code   int
+					params []interface{}
+					cell   *game.JI
+				}
+				for i, e := range []expected{
+					{code: service.ErrValidationFieldHasInvalidData, params: []interface{}{0, 2, byte(250)}, cell: &game.JI{J: 2, I: 0}},
+					{code: service.ErrValidationFieldIsNotRectangular, params: []interface{}{1, 4, 3}},
+					{code: service.ErrValidationFieldHasInvalidData, params: []interface{}{2, 0, byte(251)}, cell: &game.JI{J: 0, I: 2}},
+					{code: service.ErrValidationFieldIsNotRectangular, params: []interface{}{3, 2, 3}},
+				} {
+					Expect(errs[i].Code).To(Equal(e.code), "error %d", i)
+					Expect(errs[i].Params).To(Equal(e.params), "error %d", i)
+					Expect(errs[i].Cell).To(Equal(e.cell), "error %d", i)
+				}
+
+				Err := p.Validate()
+				Expect(Err).NotTo(BeNil())
+				Expect(*Err).To(Equal(errs[0]))
+			})
+			It("checks that the dimension limit is taken from the rules", func() {
+				p := game.Position{Maze: make([][]byte, game.MaxDim+1)}
+				for i := range p.Maze {
+					p.Maze[i] = make([]byte, game.MaxDim+1)
+				}
+				p.Maze[1][1] = game.CellPlayer
+				Err := p.Validate()
+				Expect(Err).NotTo(BeNil())
+				Expect(Err.Code).To(Equal(service.ErrValidationFieldIsTooLarge))
+
+				p.Rules = game.DefaultRules()
+				p.Rules.MaxDim = game.MaxDim + 1
+				Expect(p.Validate()).To(BeNil())
+				p.Rules.MaxDim = game.MaxDim - 1
+				Err = p.Validate()
+				Expect(Err).NotTo(BeNil())
+				Expect(Err.Params).To(Equal([]interface{}{game.MaxDim + 1, game.MaxDim - 1}))
+			})
+			It("checks that the number of search states is bounded by the rules", func() {
+				p := game.Position{Maze: [][]byte{
+					{1, 1, 1, 1},
+					{1, 4, 5, 0},
+					{1, 1, 1, 1},
+				}, Rules: game.DefaultRules()}
+				// 3 passable cells, 4 HP and 2 subsets of potions used
+				p.Rules.MaxStates = 24
+				Expect(p.Validate()).To(BeNil())
+				p.Rules.MaxStates = 23
+				Err := p.Validate()
+				Expect(Err).NotTo(BeNil())
+				Expect(Err.Code).To(Equal(service.ErrValidationTooManyPotions))
+				Expect(Err.Params).To(Equal([]interface{}{1, 0}))
+				p.Rules.MaxStates = 11
+				Err = p.Validate()
+				Expect(Err).NotTo(BeNil())
+				Expect(Err.Code).To(Equal(service.ErrValidationStateSpaceIsTooLarge))
+				Expect(Err.Params).To(Equal([]interface{}{float64(12), 11}))
+
+				p.Rules.MaxStates = 0
+				p.PeriodicTraps = []game.PeriodicTrap{{Cell: game.JI{J: 3, I: 1}, Period: 120, Active: 1}}
+				Expect(p.Validate()).To(BeNil())
+			})
 		})
 		Context("traversal", func() {
 			findStart := func(maze [][]byte) (i, j int) {
@@ -209,23 +279,15 @@ var _ = Describe("testing position", func() {
 				Expect(exitI).To(BeNumerically(">=", 0))
 				Expect(exitJ).To(BeNumerically(">=", 0))
 
-				graph, err := p.ToGraph()
-				Expect(err).NotTo(HaveOccurred())
-				visitedOrder := []game.JI{}
-				visitCB := func(cell game.JI) {
-					visitedOrder = append(visitedOrder, cell)
-				}
-				bt := game.BreadthFirstSearch(graph,
-					graph.Vertices[game.JI{startJ, startI}],
-					graph.Vertices[game.JI{exitJ, exitI}],
-					visitCB)
-				fmt.Println(visitedOrder)
-				Expect(bt).NotTo(BeNil())
+				path, Err := p.Solve()
+				Expect(Err).To(BeNil())
+				Expect(path.Cells[0]).To(Equal(game.JI{startJ, startI}))
+				Expect(path.Cells[len(path.Cells)-1]).To(Equal(game.JI{exitJ, exitI}))
 
 				sMazeRows := strings.Split(sMaze, "\n")
 				fmt.Println(sMazeRows)
-				for _, v := range bt {
-					sMazeRows[v.Idx.I] = sMazeRows[v.Idx.I][:v.Idx.J] + "*" + sMazeRows[v.Idx.I][v.Idx.J+1:]
+				for _, c := range path.Cells {
+					sMazeRows[c.I] = sMazeRows[c.I][:c.J] + "*" + sMazeRows[c.I][c.J+1:]
 				}
 				fmt.Println(strings.Join(sMazeRows, "\n"))
 				Expect(sMazeRows).To(Equal([]string{
@@ -276,23 +338,15 @@ var _ = Describe("testing position", func() {
 				Expect(exitI).To(BeNumerically(">=", 0))
 				Expect(exitJ).To(BeNumerically(">=", 0))
 
-				graph, err := p.ToGraph()
-				Expect(err).NotTo(HaveOccurred())
-				visitedOrder := []game.JI{}
-				visitCB := func(cell game.JI) {
-					visitedOrder = append(visitedOrder, cell)
-				}
-				bt := game.BreadthFirstSearch(graph,
-					graph.Vertices[game.JI{startJ, startI}],
-					graph.Vertices[game.JI{exitJ, exitI}],
-					visitCB)
-				fmt.Println(visitedOrder)
-				Expect(bt).NotTo(BeNil())
+				path, Err := p.Solve()
+				Expect(Err).To(BeNil())
+				Expect(path.Cells[0]).To(Equal(game.JI{startJ, startI}))
+				Expect(path.Cells[len(path.Cells)-1]).To(Equal(game.JI{exitJ, exitI}))
 
 				sMazeRows := strings.Split(sMaze, "\n")
 				fmt.Println(sMazeRows)
-				for _, v := range bt {
-					sMazeRows[v.Idx.I] = sMazeRows[v.Idx.I][:v.Idx.J] + "*" + sMazeRows[v.Idx.I][v.Idx.J+1:]
+				for _, c := range path.Cells {
+					sMazeRows[c.I] = sMazeRows[c.I][:c.J] + "*" + sMazeRows[c.I][c.J+1:]
 				}
 				fmt.Println(strings.Join(sMazeRows, "\n"))
 				Expect(sMazeRows).To(Equal([]string{
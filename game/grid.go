@@ -0,0 +1,106 @@
+package game
+
+// JI is j and i coordinate pair
+type JI struct {
+	J int `json:"x"`
+	I int `json:"y"`
+}
+
+// Grid is a compact graph of maze cells used by solvers. Cells are indexed by i*Width+j as in the flat maze
+// of model.Level, arcs of all cells are kept in flat arrays, so the grid takes a few allocations
+// regardless of the maze size.
+type Grid struct {
+	Width, Height int
+	Cells         []byte // values of cells by their indices
+
+	first []int32 // arcs from the cell k are arcs[first[k]:first[k+1]]
+	arcs  []int32 // indices of cells the arcs lead to, ordered by row and column for each cell
+	costs []int32 // move costs of arcs
+
+	// task-specific
+	StartingHP    int
+	Palette       Palette
+	Teleports     map[JI]JI           // partners of teleporter cells
+	PeriodicTraps map[JI]PeriodicTrap // traps dealing damage on a cycle
+	Enemies       []Enemy             // enemies patrolling the grid
+	Tracer        SearchTracer        // receives events of searches in the grid, nil means none
+	MaxStates     int                 // max number of states a search in the grid may create, zero means MaxStates
+}
+
+// newGrid returns a pointer to a new grid of the given size with the given cell values and no arcs yet
+func newGrid(width, height int, cells []byte, rules *Rules) *Grid {
+	return &Grid{
+		Width:         width,
+		Height:        height,
+		Cells:         cells,
+		first:         make([]int32, 1, width*height+1),
+		StartingHP:    rules.StartingHP,
+		Palette:       rules.Palette,
+		Teleports:     map[JI]JI{},
+		PeriodicTraps: map[JI]PeriodicTrap{},
+		MaxStates:     rules.maxStates(),
+	}
+}
+
+// addArc adds an arc with the given move cost from the last cell having arcs added to the cell with index to.
+// Arcs of each cell should be added in a row, the arc to the same cell replaces the existing one.
+func (g *Grid) addArc(to, cost int) {
+	from := g.first[len(g.first)-1]
+	for k := int(from); k < len(g.arcs); k++ {
+		if g.arcs[k] == int32(to) {
+			g.costs[k] = int32(cost)
+			return
+		}
+	}
+	g.arcs, g.costs = append(g.arcs, int32(to)), append(g.costs, int32(cost))
+	// keep arcs of the cell ordered by the target index
+	for k := len(g.arcs) - 1; k > int(from) && g.arcs[k] < g.arcs[k-1]; k-- {
+		g.arcs[k], g.arcs[k-1] = g.arcs[k-1], g.arcs[k]
+		g.costs[k], g.costs[k-1] = g.costs[k-1], g.costs[k]
+	}
+}
+
+// endCell finishes adding arcs of the current cell, further arcs are added to the next one
+func (g *Grid) endCell() { g.first = append(g.first, int32(len(g.arcs))) }
+
+// Index of the cell c in the flat arrays
+func (g *Grid) Index(c JI) int { return c.I*g.Width + c.J }
+
+// Cell with the index k
+func (g *Grid) Cell(k int) JI { return JI{J: k % g.Width, I: k / g.Width} }
+
+// Has returns true if the cell c is inside the grid
+func (g *Grid) Has(c JI) bool { return c.I >= 0 && c.I < g.Height && c.J >= 0 && c.J < g.Width }
+
+// Value of the cell c
+func (g *Grid) Value(c JI) byte { return g.Cells[g.Index(c)] }
+
+// Arcs returns indices of cells the arcs from the cell with index k lead to and move costs of the arcs.
+// Returned slices are shared with the grid and should not be modified.
+func (g *Grid) Arcs(k int) (to, costs []int32) {
+	if k+1 >= len(g.first) {
+		return nil, nil
+	}
+	return g.arcs[g.first[k]:g.first[k+1]], g.costs[g.first[k]:g.first[k+1]]
+}
+
+// Neighbours returns cells the player can move to from the cell c ordered by row and column
+func (g *Grid) Neighbours(c JI) []JI {
+	to, _ := g.Arcs(g.Index(c))
+	res := make([]JI, len(to))
+	for k, n := range to {
+		res[k] = g.Cell(int(n))
+	}
+	return res
+}
+
+// Cost of moving from the cell to its neighbour, one if there is no such arc
+func (g *Grid) Cost(from, to JI) int {
+	arcs, costs := g.Arcs(g.Index(from))
+	for k, n := range arcs {
+		if int(n) == g.Index(to) {
+			return int(costs[k])
+		}
+	}
+	return 1
+}
@@ -0,0 +1,60 @@
+package game_test
+
+import (
+	"github.com/mtfelian/gjg-test-task/game"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("grid", func() {
+	It("checks that cells are indexed as in the storage format", func() {
+		p := game.Position{Maze: [][]byte{
+			{1, 1, 1, 1},
+			{1, 4, 0, game.CellMud},
+			{1, 0, 1, 1},
+		}, X: 4, Y: 3}
+		grid, err := p.ToGrid()
+		Expect(err).NotTo(HaveOccurred())
+		Expect(grid.Width).To(Equal(4))
+		Expect(grid.Height).To(Equal(3))
+		Expect(grid.Cells).To(Equal(p.ToStorage().Maze))
+		for k := range grid.Cells {
+			Expect(grid.Index(grid.Cell(k))).To(Equal(k), "cell %d", k)
+		}
+		Expect(grid.Value(game.JI{J: 3, I: 1})).To(Equal(byte(game.CellMud)))
+		Expect(grid.Has(game.JI{J: 3, I: 2})).To(BeTrue())
+		Expect(grid.Has(game.JI{J: 4, I: 1})).To(BeFalse())
+		Expect(grid.Has(game.JI{J: 0, I: -1})).To(BeFalse())
+	})
+
+	It("checks arcs of cells, their order and costs", func() {
+		p := game.Position{
+			Maze: [][]byte{
+				{1, 1, 1, 1, 1, 1},
+				{1, game.CellTeleporter, 4, game.CellMud, game.CellTeleporter, 0},
+				{1, 0, game.CellConveyorRight, 1, 1, 1},
+			},
+			Teleporters: []game.Teleporter{{A: game.JI{J: 1, I: 1}, B: game.JI{J: 4, I: 1}, Cost: 5}},
+		}
+		grid, err := p.ToGrid()
+		Expect(err).NotTo(HaveOccurred())
+		Expect(grid.Neighbours(game.JI{J: 2, I: 1})).To(Equal([]game.JI{{J: 1, I: 1}, {J: 3, I: 1}, {J: 2, I: 2}}))
+		Expect(grid.Neighbours(game.JI{J: 1, I: 1})).To(Equal([]game.JI{{J: 2, I: 1}, {J: 4, I: 1}, {J: 1, I: 2}}))
+		Expect(grid.Neighbours(game.JI{J: 2, I: 2})).To(BeEmpty()) // the conveyor leads to a wall
+		Expect(grid.Neighbours(game.JI{J: 0, I: 0})).To(BeEmpty())
+
+		Expect(grid.Cost(game.JI{J: 2, I: 1}, game.JI{J: 3, I: 1})).To(Equal(3))
+		Expect(grid.Cost(game.JI{J: 3, I: 1}, game.JI{J: 2, I: 1})).To(Equal(1))
+		Expect(grid.Cost(game.JI{J: 1, I: 1}, game.JI{J: 4, I: 1})).To(Equal(5))
+		Expect(grid.Teleports).To(Equal(map[game.JI]game.JI{{J: 1, I: 1}: {J: 4, I: 1}, {J: 4, I: 1}: {J: 1, I: 1}}))
+
+		to, costs := grid.Arcs(grid.Index(game.JI{J: 4, I: 1}))
+		Expect(to).To(Equal([]int32{7, 9, 11}))
+		Expect(costs).To(Equal([]int32{5, 3, 1}))
+	})
+
+	It("checks that a non-rectangular position is not converted", func() {
+		_, err := game.Position{Maze: [][]byte{{1, 4}, {0}}}.ToGrid()
+		Expect(err).To(HaveOccurred())
+	})
+})
@@ -0,0 +1,73 @@
+package game
+
+import "math"
+
+// HPCurvePoint is the minimum survivable path for the starting HP
+type HPCurvePoint struct {
+	StartingHP int  `json:"starting_hp"`
+	Solvable   bool `json:"solvable"`
+	Length     int  `json:"length"`
+	Cost       int  `json:"cost"`
+	Damage     int  `json:"damage"`
+}
+
+// HPAnalysis contains the smallest starting HP which makes the position survivable and the HP curve:
+// minimum survivable paths for each starting HP from one up to the one where the curve flattens,
+// that is, the path is the same as with unlimited HP
+type HPAnalysis struct {
+	MinStartingHP int            `json:"min_starting_hp"`
+	Curve         []HPCurvePoint `json:"curve"`
+}
+
+// AnalyzeHP returns the analysis of the position survivability depending on the starting HP
+func (p Position) AnalyzeHP() (*HPAnalysis, *Error) {
+	grid, start, exits, Err := p.searchParams()
+	if Err != nil {
+		return nil, Err
+	}
+	solve := func(hp int) (*Path, error) {
+		grid.StartingHP = hp
+		return MinSurvivablePath(grid, start, exits)
+	}
+
+	// the path with unlimited HP is survivable with HP greater than its damage,
+	// and survivability does not decrease with HP
+	unlimited, err := solve(math.MaxInt32)
+	if Err := searchError(err, start); Err != nil {
+		return nil, Err
+	}
+	low, high := 1, unlimited.Damage+1
+	for low < high {
+		middle := (low + high) / 2
+		_, err := solve(middle)
+		switch {
+		case err == ErrNoSurvivablePath:
+			low = middle + 1
+		case err != nil:
+			return nil, searchError(err, start)
+		default:
+			high = middle
+		}
+	}
+
+	res := &HPAnalysis{MinStartingHP: low}
+	for hp := 1; hp < res.MinStartingHP; hp++ {
+		res.Curve = append(res.Curve, HPCurvePoint{StartingHP: hp})
+	}
+	for hp := res.MinStartingHP; ; hp++ {
+		path, err := solve(hp)
+		if err != nil {
+			return nil, searchError(err, start)
+		}
+		res.Curve = append(res.Curve, HPCurvePoint{
+			StartingHP: hp,
+			Solvable:   true,
+			Length:     path.Length,
+			Cost:       path.Cost,
+			Damage:     path.Damage,
+		})
+		if path.Cost == unlimited.Cost {
+			return res, nil
+		}
+	}
+}
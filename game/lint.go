@@ -0,0 +1,212 @@
+package game
+
+import (
+	"fmt"
+
+	"github.com/mtfelian/gjg-test-task/service"
+)
+
+// reachableCells returns indices of cells reachable from the cell with index from in the grid g regardless of HP
+func reachableCells(g *Grid, from int) []bool {
+	res := make([]bool, len(g.Cells))
+	res[from] = true
+	queue := []int32{int32(from)}
+	for len(queue) > 0 {
+		current := queue[0]
+		queue = queue[1:]
+		to, _ := g.Arcs(int(current))
+		for _, n := range to {
+			if !res[n] {
+				res[n] = true
+				queue = append(queue, n)
+			}
+		}
+	}
+	return res
+}
+
+// Lint the position and return non-fatal warnings about its design.
+// The position is expected to be structurally valid. Survivable paths are not checked for positions
+// having more cells than MaxLintCells of the rules.
+func (p Position) Lint() (warnings Errors) {
+	grid, err := p.ToGrid()
+	if err != nil {
+		return
+	}
+
+	var starts []JI
+	for i, row := range p.Maze {
+		for j, cell := range row {
+			if cell == CellPlayer {
+				starts = append(starts, JI{j, i})
+			}
+		}
+	}
+	for k := 1; k < len(starts); k++ {
+		start := starts[k]
+		warnings = append(warnings, Error{
+			Code:    service.WarnMultipleStarts,
+			Message: fmt.Sprintf("Cell (%d,%d) is an additional player starting position", start.I, start.J),
+			Params:  []interface{}{start.I, start.J},
+			Cell:    &start,
+		})
+	}
+
+	exits := p.Exits()
+	isExit := make(map[JI]bool, len(exits))
+	for _, exit := range exits {
+		isExit[exit] = true
+	}
+	warnings = append(warnings, lintDeadEnds(p, grid, isExit)...)
+	if len(starts) == 0 {
+		return
+	}
+
+	reachable := reachableCells(grid, grid.Index(starts[0]))
+	warnings = append(warnings, lintUnreachableRegions(p, grid, reachable)...)
+
+	if maxCells := p.rules().maxLintCells(); len(grid.Cells) > maxCells {
+		return append(warnings, Error{
+			Code:    service.WarnLintSkipped,
+			Message: fmt.Sprintf("Position has more than %d cells, exits and traps are not checked", maxCells),
+			Params:  []interface{}{len(grid.Cells), maxCells},
+		})
+	}
+
+	// states reachable alive from the start and ones of them leading to an exit alive
+	nodes, arcs, err := newSearcher(grid).explore(starts[0])
+	if err != nil {
+		return append(warnings, Error{
+			Code:    service.WarnLintBudgetExceeded,
+			Message: "Position has too many states to check survivable paths, exits and traps are not checked",
+			Params:  []interface{}{grid.MaxStates},
+		})
+	}
+	first, predecessors := reversed(len(nodes), arcs)
+	aliveCells, leadingToExit := make([]bool, len(grid.Cells)), make([]bool, len(nodes))
+	var queue []int32
+	for k, node := range nodes {
+		aliveCells[node.state.cell] = true
+		if isExit[grid.Cell(int(node.state.cell))] {
+			leadingToExit[k] = true
+			queue = append(queue, int32(k))
+		}
+	}
+	for len(queue) > 0 {
+		current := queue[0]
+		queue = queue[1:]
+		for _, prev := range predecessors[first[current]:first[current+1]] {
+			if !leadingToExit[prev] {
+				leadingToExit[prev] = true
+				queue = append(queue, prev)
+			}
+		}
+	}
+
+	for _, exit := range exits {
+		exit := exit
+		if !aliveCells[grid.Index(exit)] {
+			warnings = append(warnings, Error{
+				Code:    service.WarnUnreachableExit,
+				Message: fmt.Sprintf("Exit (%d,%d) can not be reached alive", exit.I, exit.J),
+				Params:  []interface{}{exit.I, exit.J},
+				Cell:    &exit,
+			})
+		}
+	}
+
+	// a trap is useful if some path through it from the start to an exit is survivable
+	usefulTraps := make([]bool, len(grid.Cells))
+	for k, leads := range leadingToExit {
+		if leads {
+			usefulTraps[nodes[k].state.cell] = true
+		}
+	}
+	for i, row := range p.Maze {
+		for j, cell := range row {
+			// unreachable traps are reported as a part of a region
+			if k := i*grid.Width + j; grid.Palette.Damage(cell) == 0 || !reachable[k] || usefulTraps[k] {
+				continue
+			}
+			warnings = append(warnings, Error{
+				Code:    service.WarnUselessTrap,
+				Message: fmt.Sprintf("Trap (%d,%d) is not on any survivable path", i, j),
+				Params:  []interface{}{i, j},
+				Cell:    &JI{j, i},
+			})
+		}
+	}
+	return
+}
+
+// lintDeadEnds returns warnings for ends of dead-end corridors: non-exit passable cells with the only neighbour.
+// Neighbours are counted regardless of edge directions.
+func lintDeadEnds(p Position, g *Grid, isExit map[JI]bool) (warnings Errors) {
+	var arcs [][2]int32
+	for k := range g.Cells {
+		to, _ := g.Arcs(k)
+		for _, n := range to {
+			arcs = append(arcs, [2]int32{int32(k), n})
+		}
+	}
+	first, from := reversed(len(g.Cells), arcs)
+	// neighbours returns the number of distinct cells linked with the cell k by arcs in any direction
+	neighbours := func(k int) int {
+		to, _ := g.Arcs(k)
+		res := len(to)
+	incoming:
+		for _, n := range from[first[k]:first[k+1]] {
+			for _, m := range to {
+				if n == m {
+					continue incoming
+				}
+			}
+			res++
+		}
+		return res
+	}
+
+	for i, row := range p.Maze {
+		for j, cell := range row {
+			if !g.Palette.Passable(cell) || cell == CellPlayer || isExit[JI{j, i}] || neighbours(i*g.Width+j) != 1 {
+				continue
+			}
+			warnings = append(warnings, Error{
+				Code:    service.WarnDeadEnd,
+				Message: fmt.Sprintf("Cell (%d,%d) is a dead end", i, j),
+				Params:  []interface{}{i, j},
+				Cell:    &JI{j, i},
+			})
+		}
+	}
+	return
+}
+
+// lintUnreachableRegions returns a warning for each connected region of passable cells
+// which can not be reached from the start, reachable contains cells reachable from the start.
+// A region consists of unreachable cells not reported yet which can be reached from its first cell.
+func lintUnreachableRegions(p Position, g *Grid, reachable []bool) (warnings Errors) {
+	seen := make([]bool, len(g.Cells))
+	for i, row := range p.Maze {
+		for j, cell := range row {
+			k := i*g.Width + j
+			if reachable[k] || !g.Palette.Passable(cell) || seen[k] {
+				continue
+			}
+			size := 0
+			for regionCell, inRegion := range reachableCells(g, k) {
+				if inRegion && !reachable[regionCell] && !seen[regionCell] {
+					seen[regionCell] = true
+					size++
+				}
+			}
+			warnings = append(warnings, Error{
+				Code:    service.WarnUnreachableRegion,
+				Message: fmt.Sprintf("Region of %d cells starting at (%d,%d) can not be reached", size, i, j),
+				Params:  []interface{}{size, i, j},
+				Cell:    &JI{j, i},
+			})
+		}
+	}
+	return
+}
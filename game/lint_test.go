@@ -0,0 +1,111 @@
+package game_test
+
+import (
+	"github.com/mtfelian/gjg-test-task/game"
+	"github.com/mtfelian/gjg-test-task/service"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("lint", func() {
+	It("checks that lint warnings are found", func() {
+		p := game.Position{Maze: [][]byte{
+			{1, 1, 1, 0, 1, 1, 1},
+			{1, 4, 0, 0, 0, 2, 1},
+			{1, 1, 1, 1, 1, 3, 1},
+			{1, 4, 0, 1, 1, 3, 1},
+			{1, 1, 0, 1, 1, 1, 1},
+		}}
+		type expected struct {
+			code   int
+			params []interface{}
+			cell   game.JI
+		}
+		warnings := p.Lint()
+		Expect(warnings).To(HaveLen(6))
+		for i, e := range []expected{
+			{code: service.WarnMultipleStarts, params: []interface{}{3, 1}, cell: game.JI{J: 1, I: 3}},
+			{code: service.WarnDeadEnd, params: []interface{}{3, 5}, cell: game.JI{J: 5, I: 3}},
+			{code: service.WarnUnreachableRegion, params: []interface{}{3, 3, 1}, cell: game.JI{J: 1, I: 3}},
+			{code: service.WarnUnreachableExit, params: []interface{}{4, 2}, cell: game.JI{J: 2, I: 4}},
+			{code: service.WarnUselessTrap, params: []interface{}{2, 5}, cell: game.JI{J: 5, I: 2}},
+			{code: service.WarnUselessTrap, params: []interface{}{3, 5}, cell: game.JI{J: 5, I: 3}},
+		} {
+			Expect(warnings[i].Code).To(Equal(e.code), "warning %d", i)
+			Expect(warnings[i].Params).To(Equal(e.params), "warning %d", i)
+			Expect(*warnings[i].Cell).To(Equal(e.cell), "warning %d", i)
+		}
+	})
+
+	It("checks that survivable paths are not checked above the state budget", func() {
+		p := game.Position{Maze: [][]byte{
+			{1, 1, 1, 1, 1, 1},
+			{1, 4, 0, 2, 0, 0},
+			{1, 1, 1, 1, 1, 1},
+		}, Rules: game.DefaultRules()}
+		p.Rules.MaxStates = 3
+		warnings := p.Lint()
+		Expect(warnings).To(HaveLen(1))
+		Expect(warnings[0].Code).To(Equal(service.WarnLintBudgetExceeded))
+		Expect(warnings[0].Params).To(Equal([]interface{}{3}))
+
+		p.Rules.MaxStates = 0
+		Expect(p.Lint()).To(BeEmpty())
+	})
+
+	It("checks that survivable paths are not checked above the max number of cells", func() {
+		p := game.Position{Maze: [][]byte{
+			{1, 1, 1, 1, 1, 1},
+			{1, 4, 0, 2, 1, 0},
+			{1, 1, 1, 1, 1, 1},
+		}, Rules: game.DefaultRules()}
+		codes := func(warnings game.Errors) (res []int) {
+			for _, w := range warnings {
+				res = append(res, w.Code)
+			}
+			return
+		}
+		p.Rules.MaxLintCells = 17
+		warnings := p.Lint()
+		Expect(codes(warnings)).To(Equal([]int{
+			service.WarnDeadEnd, service.WarnUnreachableRegion, service.WarnLintSkipped,
+		}))
+		Expect(warnings[2].Params).To(Equal([]interface{}{18, 17}))
+
+		p.Rules.MaxLintCells = 18
+		Expect(codes(p.Lint())).To(Equal([]int{
+			service.WarnDeadEnd, service.WarnUnreachableRegion, service.WarnUnreachableExit, service.WarnUselessTrap,
+		}))
+	})
+
+	It("checks that directions of conveyors are honoured", func() {
+		p := game.Position{Maze: [][]byte{
+			{1, 1, 1, 1, 1},
+			{1, 0, game.CellConveyorRight, 4, 0},
+			{1, 1, 1, 1, 1},
+		}}
+		warnings := p.Lint()
+		Expect(warnings).To(HaveLen(2))
+		Expect(warnings[0].Code).To(Equal(service.WarnDeadEnd))
+		Expect(warnings[0].Params).To(Equal([]interface{}{1, 1}))
+		Expect(warnings[1].Code).To(Equal(service.WarnUnreachableRegion))
+		Expect(warnings[1].Params).To(Equal([]interface{}{1, 1, 1}))
+	})
+
+	It("checks that the README example has no warnings except the dead ends", func() {
+		p := game.Position{Maze: [][]byte{
+			{1, 1, 1, 1, 0, 1, 1, 1},
+			{1, 0, 0, 0, 0, 0, 0, 1},
+			{1, 0, 1, 1, 1, 3, 1, 1},
+			{1, 0, 0, 0, 1, 0, 2, 1},
+			{1, 1, 1, 0, 1, 1, 0, 1},
+			{1, 0, 0, 0, 1, 0, 0, 1},
+			{1, 0, 1, 1, 1, 0, 1, 1},
+			{1, 0, 0, 4, 0, 0, 0, 1},
+			{1, 1, 1, 1, 1, 1, 1, 1},
+		}}
+		for _, w := range p.Lint() {
+			Expect(w.Code).To(Equal(service.WarnDeadEnd))
+		}
+	})
+})
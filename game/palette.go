@@ -0,0 +1,216 @@
+package game
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Tile describes a kind of maze cell
+type Tile struct {
+	ID       byte `json:"id"`
+	Passable bool `json:"passable"`
+	Damage   int  `json:"damage"` // damage taken by a player entering the tile
+	// Chance is a probability of the damage to be dealt, zero means it is dealt always
+	Chance float64 `json:"chance"`
+	Heal   int     `json:"heal"` // HP restored once per path by a potion tile, up to the starting HP
+	Key    string  `json:"key"`  // color of the key picked up by a player entering the tile
+	Door   string  `json:"door"` // color of the key required to enter the tile
+	Exit   bool    `json:"exit"` // the level is completed by entering the tile, see the exit mode of the rules
+	Cost   int     `json:"cost"` // move cost of entering the tile, zero means one
+	// Teleporter tiles are linked in pairs by the level, entering one moves the player to another
+	Teleporter bool `json:"teleporter"`
+	// Direction is the only one of "up", "down", "left" and "right" the player can leave the tile in, empty means any
+	Direction string `json:"direction"`
+	Glyph     string `json:"glyph"` // symbol to display the tile
+}
+
+// directions of moves by their names
+var directions = map[string]JI{
+	"up":    {J: 0, I: -1},
+	"down":  {J: 0, I: 1},
+	"left":  {J: -1, I: 0},
+	"right": {J: 1, I: 0},
+}
+
+// Palette contains tiles by their IDs
+type Palette map[byte]Tile
+
+// DefaultPalette returns the palette of tiles described in the README
+func DefaultPalette() Palette {
+	palette, _ := NewPalette([]Tile{
+		{ID: CellOpen, Passable: true, Glyph: "."},
+		{ID: CellWall, Glyph: "#"},
+		{ID: CellPit, Passable: true, Damage: 1, Glyph: "^"},
+		{ID: CellArrow, Passable: true, Damage: 2, Glyph: ">"},
+		{ID: CellPlayer, Passable: true, Glyph: "@"},
+		{ID: CellPotion, Passable: true, Heal: 2, Glyph: "+"},
+		{ID: CellKeyRed, Passable: true, Key: "red", Glyph: "r"},
+		{ID: CellKeyGreen, Passable: true, Key: "green", Glyph: "g"},
+		{ID: CellKeyBlue, Passable: true, Key: "blue", Glyph: "b"},
+		{ID: CellDoorRed, Passable: true, Door: "red", Glyph: "R"},
+		{ID: CellDoorGreen, Passable: true, Door: "green", Glyph: "G"},
+		{ID: CellDoorBlue, Passable: true, Door: "blue", Glyph: "B"},
+		{ID: CellTeleporter, Passable: true, Teleporter: true, Glyph: "O"},
+		{ID: CellConveyorUp, Passable: true, Direction: "up", Glyph: "↑"},
+		{ID: CellConveyorDown, Passable: true, Direction: "down", Glyph: "↓"},
+		{ID: CellConveyorLeft, Passable: true, Direction: "left", Glyph: "←"},
+		{ID: CellConveyorRight, Passable: true, Direction: "right", Glyph: "→"},
+		{ID: CellRandomTrap, Passable: true, Damage: 2, Chance: 0.5, Glyph: "%"},
+		{ID: CellExit, Passable: true, Exit: true, Glyph: "E"},
+		{ID: CellMud, Passable: true, Cost: 3, Glyph: ":"},
+		{ID: CellWater, Passable: true, Cost: 2, Glyph: "~"},
+	})
+	return palette
+}
+
+// NewPalette returns a palette consisting of the given tiles
+func NewPalette(tiles []Tile) (Palette, error) {
+	res := make(Palette, len(tiles))
+	for _, tile := range tiles {
+		if _, ok := res[tile.ID]; ok {
+			return nil, fmt.Errorf("tile %d is defined more than once", tile.ID)
+		}
+		if tile.Damage < 0 {
+			return nil, fmt.Errorf("tile %d has negative damage %d", tile.ID, tile.Damage)
+		}
+		if tile.Heal < 0 {
+			return nil, fmt.Errorf("tile %d has negative heal %d", tile.ID, tile.Heal)
+		}
+		if tile.Cost < 0 {
+			return nil, fmt.Errorf("tile %d has negative cost %d", tile.ID, tile.Cost)
+		}
+		if tile.Chance < 0 || tile.Chance > 1 {
+			return nil, fmt.Errorf("tile %d has chance %v out of [0, 1]", tile.ID, tile.Chance)
+		}
+		if _, ok := directions[tile.Direction]; !ok && tile.Direction != "" {
+			return nil, fmt.Errorf("tile %d has unknown direction %q", tile.ID, tile.Direction)
+		}
+		res[tile.ID] = tile
+	}
+	if tile, ok := res[CellPlayer]; !ok || !tile.Passable {
+		return nil, fmt.Errorf("player starting position tile %d should be defined and passable", CellPlayer)
+	}
+	return res, nil
+}
+
+// Has returns true if the palette contains a tile with the given ID
+func (p Palette) Has(value byte) bool {
+	_, ok := p[value]
+	return ok
+}
+
+// Passable returns true if a player can enter the tile with the given ID
+func (p Palette) Passable(value byte) bool { return p[value].Passable }
+
+// Damage returns damage taken by a player entering the tile with the given ID
+func (p Palette) Damage(value byte) int { return p[value].Damage }
+
+// Cost returns the move cost of entering the tile with the given ID
+func (p Palette) Cost(value byte) int {
+	if cost := p[value].Cost; cost > 0 {
+		return cost
+	}
+	return 1
+}
+
+// HitChance returns a probability of the damage of the tile with the given ID to be dealt
+func (p Palette) HitChance(value byte) float64 {
+	if chance := p[value].Chance; chance > 0 {
+		return chance
+	}
+	return 1
+}
+
+// CanLeave returns true if a player can leave the tile with the given ID by the move d
+func (p Palette) CanLeave(value byte, d JI) bool {
+	direction := p[value].Direction
+	return direction == "" || directions[direction] == d
+}
+
+// Glyph returns the symbol to display the tile with the given ID, "?" for unknown tiles
+func (p Palette) Glyph(value byte) string {
+	if tile, ok := p[value]; ok && tile.Glyph != "" {
+		return tile.Glyph
+	}
+	return "?"
+}
+
+// exit modes
+const (
+	ExitModeTile   = "tile"   // exits are exit tiles only
+	ExitModeBorder = "border" // exits are open cells on the border only
+	ExitModeBoth   = "both"   // exits are exit tiles and open cells on the border
+)
+
+// IsValidExitMode returns true if mode is a known exit mode
+func IsValidExitMode(mode string) bool {
+	return mode == ExitModeTile || mode == ExitModeBorder || mode == ExitModeBoth
+}
+
+// Rules of the game which can be configured per deployment
+type Rules struct {
+	Palette      Palette
+	StartingHP   int
+	TeleportCost int    // default move cost of the teleportation
+	ExitMode     string // what counts as an exit
+	MaxDim       int    // max number of rows and columns of the maze, zero means MaxDim
+	MaxStates    int    // max number of states of the level and of a search in it, zero means MaxStates
+	MaxLintCells int    // max number of cells of the maze to check survivable paths by lint, zero means MaxLintCells
+}
+
+// DefaultRules returns the rules described in the README
+func DefaultRules() *Rules {
+	return &Rules{
+		Palette:      DefaultPalette(),
+		StartingHP:   startingHP,
+		TeleportCost: teleportCost,
+		ExitMode:     ExitModeBoth,
+	}
+}
+
+// maxDim returns the max number of rows and columns of the maze
+func (r *Rules) maxDim() int {
+	if r.MaxDim > 0 {
+		return r.MaxDim
+	}
+	return MaxDim
+}
+
+// maxStates returns the max number of states of the level and of a search in it
+func (r *Rules) maxStates() int {
+	if r.MaxStates > 0 {
+		return r.MaxStates
+	}
+	return MaxStates
+}
+
+// maxLintCells returns the max number of cells of the maze to check survivable paths by lint
+func (r *Rules) maxLintCells() int {
+	if r.MaxLintCells > 0 {
+		return r.MaxLintCells
+	}
+	return MaxLintCells
+}
+
+// rules returns the rules of the position, the default ones if they are not set
+func (p Position) rules() *Rules {
+	if p.Rules == nil {
+		return DefaultRules()
+	}
+	return p.Rules
+}
+
+// String renders the position with glyphs of the tiles
+func (p Position) String() string {
+	palette := p.rules().Palette
+	var sb strings.Builder
+	for i, row := range p.Maze {
+		if i > 0 {
+			sb.WriteString("\n")
+		}
+		for _, cell := range row {
+			sb.WriteString(palette.Glyph(cell))
+		}
+	}
+	return sb.String()
+}
@@ -0,0 +1,67 @@
+package game_test
+
+import (
+	"github.com/mtfelian/gjg-test-task/game"
+	"github.com/mtfelian/gjg-test-task/service"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("palette", func() {
+	It("checks that a custom palette and starting HP are used by validation and solver", func() {
+		palette, err := game.NewPalette([]game.Tile{
+			{ID: game.CellOpen, Passable: true, Glyph: "."},
+			{ID: game.CellWall, Glyph: "#"},
+			{ID: game.CellPit, Passable: true, Damage: 3, Glyph: "^"},
+			{ID: game.CellPlayer, Passable: true, Glyph: "@"},
+			{ID: 7, Passable: true, Damage: 5, Glyph: "~"},
+		})
+		Expect(err).NotTo(HaveOccurred())
+		rules := &game.Rules{Palette: palette, StartingHP: 6}
+
+		p := game.Position{
+			Maze: [][]byte{
+				{1, 1, 0, 1, 1, 1},
+				{1, 1, 2, 1, 1, 1},
+				{1, 4, 7, 0, 0, 0},
+				{1, 1, 1, 1, 1, 1},
+			},
+			Rules: rules,
+		}
+		Expect(p.Validate()).To(BeNil())
+		Expect(p.String()).To(Equal("##.###\n##^###\n#@~...\n######"))
+
+		path, Err := p.Solve()
+		Expect(Err).To(BeNil())
+		Expect(path.Length).To(Equal(4))
+		Expect(path.Damage).To(Equal(5))
+
+		By("checking that the lava tile is deadly with the default starting HP", func() {
+			p.Rules = &game.Rules{Palette: palette, StartingHP: 4}
+			path, Err = p.Solve()
+			Expect(Err).NotTo(BeNil())
+			Expect(Err.Code).To(Equal(service.ErrNoSurvivablePath))
+		})
+
+		By("checking that the arrow trap is not in the palette", func() {
+			p.Maze[1][2] = game.CellArrow
+			Err = p.Validate()
+			Expect(Err).NotTo(BeNil())
+			Expect(Err.Code).To(Equal(service.ErrValidationFieldHasInvalidData))
+		})
+	})
+
+	It("checks that an invalid palette is rejected", func() {
+		for i, tiles := range [][]game.Tile{
+			{{ID: game.CellPlayer, Passable: true}, {ID: game.CellPlayer, Passable: true}},
+			{{ID: game.CellPlayer, Passable: true}, {ID: game.CellPit, Passable: true, Damage: -1}},
+			{{ID: game.CellOpen, Passable: true}},
+			{{ID: game.CellPlayer}},
+			{{ID: game.CellPlayer, Passable: true}, {ID: game.CellConveyorUp, Passable: true, Direction: "north"}},
+			{{ID: game.CellPlayer, Passable: true}, {ID: game.CellMud, Passable: true, Cost: -1}},
+		} {
+			_, err := game.NewPalette(tiles)
+			Expect(err).To(HaveOccurred(), "case %d", i)
+		}
+	})
+})
@@ -0,0 +1,88 @@
+package game
+
+// paretoState is a search state with the damage taken to reach it
+type paretoState struct {
+	state  searchState
+	damage int
+}
+
+// paretoNode is a pareto state with the index of the node it is reached from and the move cost of reaching it
+type paretoNode struct {
+	key    paretoState
+	parent int32 // -1 for the initial state
+	cost   int
+}
+
+// ParetoPaths searches for all survivable paths in the grid g from start to exits which are not dominated
+// by another path on both move cost and damage taken. One path is returned for each such pair,
+// paths are ordered by move cost, so the first one is the minimum survivable path
+// and the last one is the path taking the least damage.
+func ParetoPaths(g *Grid, start JI, exits []JI) ([]*Path, error) {
+	if !g.Has(start) {
+		return nil, ErrNoStartVertex
+	}
+
+	s := newSearcher(g)
+	isExit := s.exits(exits)
+	initial := paretoState{state: s.initial(start)}
+	nodes := []paretoNode{{key: initial, parent: -1}}
+	index := map[paretoState]int32{initial: 0}
+	var found []int32 // nodes of exit states with strictly decreasing damage
+	queue := &stateQueue{}
+	queue.push(0, 0)
+	for queue.Len() > 0 {
+		k, cost := queue.pop()
+		current := nodes[k]
+		if cost > current.cost { // outdated queue item
+			continue
+		}
+		// damage never decreases along the path, so the state can not lead to a better exit
+		if len(found) > 0 && current.key.damage >= nodes[found[len(found)-1]].key.damage {
+			continue
+		}
+		if isExit[current.key.state.cell] {
+			// an exit state with the same cost and less damage replaces the found one
+			if n := len(found); n > 0 && nodes[found[n-1]].cost == cost {
+				found = found[:n-1]
+			}
+			found = append(found, k)
+			continue
+		}
+		for _, m := range s.moves(current.key.state) {
+			damage := m.damage + m.contact
+			next := paretoState{state: m.next, damage: current.key.damage + damage}
+			if next.state.hp -= damage; next.state.hp <= 0 { // player dies here
+				continue
+			}
+			next.state.hp = s.healed(next.state.hp, m.heal)
+			nextCost := cost + m.cost
+			n, visited := index[next]
+			switch {
+			case visited && nodes[n].cost <= nextCost:
+				continue
+			case visited:
+				nodes[n].parent, nodes[n].cost = k, nextCost
+			case s.exhausted(len(nodes)):
+				return nil, ErrStateBudgetExceeded
+			default:
+				n = int32(len(nodes))
+				index[next] = n
+				nodes = append(nodes, paretoNode{key: next, parent: k, cost: nextCost})
+			}
+			queue.push(n, nextCost)
+		}
+	}
+	if len(found) == 0 {
+		return nil, ErrNoSurvivablePath
+	}
+
+	paths := make([]*Path, len(found))
+	for n, k := range found {
+		var states []searchState
+		for ; k >= 0; k = nodes[k].parent {
+			states = append([]searchState{nodes[k].key.state}, states...)
+		}
+		paths[n] = s.survivablePath(states)
+	}
+	return paths, nil
+}
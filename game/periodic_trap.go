@@ -0,0 +1,148 @@
+package game
+
+import (
+	"fmt"
+
+	"github.com/mtfelian/gjg-test-task/service"
+	"github.com/mtfelian/gjg-test-task/storage/model"
+)
+
+// maxCycle is the max number of ticks after which all periodic traps and enemies of the level repeat
+const maxCycle = 120
+
+// PeriodicTrap is a trap cell dealing damage only on ticks where (tick + Phase) % Period is less than Active.
+// As any trap it is checked only on the tick the player enters the cell, staying in slow terrain or waiting
+// in the cell while the trap becomes active deals no damage.
+type PeriodicTrap struct {
+	Cell   JI  `json:"cell"`
+	Period int `json:"period"`
+	Phase  int `json:"phase"`
+	Active int `json:"active"` // length of the active window at the beginning of the period
+	Damage int `json:"damage"` // damage dealt while active, zero means the damage of the tile
+}
+
+// active returns true if the trap deals damage on the given tick
+func (t PeriodicTrap) active(tick int) bool { return (tick+t.Phase)%t.Period < t.Active }
+
+// damage dealt by the trap to the player entering the tile value on the given tick
+func (t PeriodicTrap) damage(palette Palette, value byte, tick int) int {
+	switch {
+	case !t.active(tick):
+		return 0
+	case t.Damage > 0:
+		return t.Damage
+	}
+	return palette.Damage(value)
+}
+
+// TrapPass describes the player passing a trap on the path
+type TrapPass struct {
+	Cell   JI  `json:"cell"`
+	Tick   int `json:"tick"`   // tick on which the player enters the trap
+	Damage int `json:"damage"` // damage taken
+}
+
+// gcd returns the greatest common divisor of a and b
+func gcd(a, b int) int {
+	for b != 0 {
+		a, b = b, a%b
+	}
+	return a
+}
+
+// cycleOf returns the least common multiple of the given positive periods, one if there are no periods
+func cycleOf(periods []int) int {
+	cycle := 1
+	for _, period := range periods {
+		if period > 0 {
+			cycle = cycle / gcd(cycle, period) * period
+		}
+	}
+	return cycle
+}
+
+// trapPeriods returns periods of the given traps
+func trapPeriods(traps []PeriodicTrap) []int {
+	res := make([]int, len(traps))
+	for k, t := range traps {
+		res[k] = t.Period
+	}
+	return res
+}
+
+// periodicTrapsToStorage converts periodic traps to the storage layer format
+func periodicTrapsToStorage(traps []PeriodicTrap) []model.PeriodicTrap {
+	res := make([]model.PeriodicTrap, len(traps))
+	for k, t := range traps {
+		res[k] = model.PeriodicTrap{
+			X:      t.Cell.J,
+			Y:      t.Cell.I,
+			Period: t.Period,
+			Phase:  t.Phase,
+			Active: t.Active,
+			Damage: t.Damage,
+		}
+	}
+	return res
+}
+
+// periodicTrapsFromStorage converts periodic traps from the storage layer format
+func periodicTrapsFromStorage(traps []model.PeriodicTrap) []PeriodicTrap {
+	if len(traps) == 0 {
+		return nil
+	}
+	res := make([]PeriodicTrap, len(traps))
+	for k, t := range traps {
+		res[k] = PeriodicTrap{
+			Cell:   JI{t.X, t.Y},
+			Period: t.Period,
+			Phase:  t.Phase,
+			Active: t.Active,
+			Damage: t.Damage,
+		}
+	}
+	return res
+}
+
+// validatePeriodicTraps checks that periodic traps of the structurally valid position p
+// are placed at distinct passable cells and have consistent timing
+func validatePeriodicTraps(p Position) (errs Errors) {
+	palette := p.rules().Palette
+	invalid := func(k int, t PeriodicTrap, reason string) Error {
+		return Error{
+			Code:    service.ErrValidationPeriodicTrapIsInvalid,
+			Message: fmt.Sprintf("Periodic trap %d at (%d,%d) %s", k, t.Cell.I, t.Cell.J, reason),
+			Params:  []interface{}{k, t.Cell.I, t.Cell.J},
+			Cell:    &JI{t.Cell.J, t.Cell.I},
+		}
+	}
+
+	placed := map[JI]bool{}
+	for k, t := range p.PeriodicTraps {
+		c := t.Cell
+		switch {
+		case c.I < 0 || c.I >= len(p.Maze) || c.J < 0 || c.J >= len(p.Maze[c.I]) || !palette.Passable(p.Maze[c.I][c.J]):
+			errs = append(errs, invalid(k, t, "is not at a passable cell"))
+		case placed[c]:
+			errs = append(errs, invalid(k, t, "is at the same cell as another one"))
+		case t.Period < 1 || t.Phase < 0 || t.Active < 1 || t.Active > t.Period:
+			errs = append(errs, invalid(k, t, fmt.Sprintf("has invalid period %d, phase %d or active window %d",
+				t.Period, t.Phase, t.Active)))
+		case t.Damage < 0:
+			errs = append(errs, invalid(k, t, fmt.Sprintf("has negative damage %d", t.Damage)))
+		}
+		placed[c] = true
+	}
+	if len(errs) > 0 {
+		return
+	}
+
+	if cycle := cycleOf(trapPeriods(p.PeriodicTraps)); cycle > maxCycle {
+		errs = append(errs, Error{
+			Code:    service.ErrValidationPeriodicTrapIsInvalid,
+			Message: fmt.Sprintf("Periodic traps repeat every %d ticks, max is %d", cycle, maxCycle),
+			Params:  []interface{}{cycle, maxCycle},
+		})
+	}
+	return
+}
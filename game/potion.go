@@ -0,0 +1,6 @@
+package game
+
+// MaxPotions is a max number of potion tiles of the level regardless of its size.
+// Each potion may double the number of states the solver and the linter explore, so the number
+// of potions of larger levels is limited by the max number of states of the rules, see validateStateSpace.
+const MaxPotions = 6
@@ -0,0 +1,173 @@
+package game
+
+import (
+	"strconv"
+	"strings"
+)
+
+// chanceEpsilon is a tolerance of comparing probabilities
+const chanceEpsilon = 1e-9
+
+// hpDistribution contains probabilities of the player to have each HP, the index is HP, zero stands for death
+type hpDistribution []float64
+
+// survival returns a probability of the player to be alive
+func (d hpDistribution) survival() (res float64) {
+	for _, p := range d[1:] {
+		res += p
+	}
+	return
+}
+
+// lowestHP returns the lowest HP the player may be alive with, zero if the player is surely dead
+func (d hpDistribution) lowestHP() int {
+	for hp := 1; hp < len(d); hp++ {
+		if d[hp] > chanceEpsilon {
+			return hp
+		}
+	}
+	return 0
+}
+
+// dominates returns true if for each HP the chance to have at least this HP with d is not less than with other
+func (d hpDistribution) dominates(other hpDistribution) bool {
+	var a, b float64
+	for hp := len(d) - 1; hp > 0; hp-- {
+		if a, b = a+d[hp], b+other[hp]; a < b-chanceEpsilon {
+			return false
+		}
+	}
+	return true
+}
+
+// key returns a string identifying the distribution, probabilities are rounded to the tolerance
+func (d hpDistribution) key() string {
+	parts := make([]string, len(d))
+	for hp, p := range d {
+		parts[hp] = strconv.FormatFloat(p, 'f', 9, 64)
+	}
+	return strings.Join(parts, ",")
+}
+
+// after returns the distribution of HP after the move m
+func (s *searcher) after(d hpDistribution, m move) hpDistribution {
+	res := make(hpDistribution, len(d))
+	res[0] = d[0]
+	// add the probability p of having the given HP after the trap
+	add := func(hp int, p float64) {
+		if hp -= m.contact; hp <= 0 {
+			res[0] += p
+			return
+		}
+		res[s.healed(hp, m.heal)] += p
+	}
+	for hp := 1; hp < len(d); hp++ {
+		p := d[hp]
+		if p == 0 {
+			continue
+		}
+		if m.damage == 0 {
+			add(hp, p)
+			continue
+		}
+		add(hp-m.damage, p*m.chance)
+		add(hp, p*(1-m.chance))
+	}
+	return res
+}
+
+// riskState is a search state with the distribution of HP instead of the HP itself
+type riskState struct {
+	state searchState // HP of the state is always zero
+	dist  string      // key of the distribution
+}
+
+// riskNode is a risk state with its distribution, the index of the node it is reached from
+// and the move cost of reaching it
+type riskNode struct {
+	key    riskState
+	dist   hpDistribution
+	parent int32 // -1 for the initial state
+	cost   int
+}
+
+// MinLikelySurvivablePath searches for the minimum path in the grid g from start to the nearest of exits
+// which is survived with the probability not less than minChance. Traps deal their damage with their chances
+// independently. RemainingHP of the path is the lowest HP the player may reach the exit with, Healed is not reported.
+func MinLikelySurvivablePath(g *Grid, start JI, exits []JI, minChance float64) (*Path, error) {
+	return minLikelySurvivablePath(g, start, exits, minChance, nil)
+}
+
+// minLikelySurvivablePath searches for the minimum likely survivable path expanding states in order of
+// their move cost plus the estimate h of the rest of the way, nil h means zero estimate
+func minLikelySurvivablePath(g *Grid, start JI, exits []JI, minChance float64, h Heuristic) (*Path, error) {
+	if !g.Has(start) {
+		return nil, ErrNoStartVertex
+	}
+	s := newSearcher(g)
+	s.h = h
+	isExit := s.exits(exits)
+
+	initialDist := make(hpDistribution, g.StartingHP+1)
+	initialDist[g.StartingHP] = 1
+	base := s.initial(start)
+	base.hp = 0
+	initial := riskState{state: base, dist: initialDist.key()}
+	nodes := []riskNode{{key: initial, dist: initialDist, parent: -1}}
+	index := map[riskState]int32{initial: 0}
+	seen := map[searchState][]int32{base: {0}} // nodes of distributions reached for each state
+	// dominated returns true if the distribution d reached with the given cost is not better than a seen one
+	dominated := func(state searchState, d hpDistribution, cost int) bool {
+		for _, n := range seen[state] {
+			if nodes[n].cost <= cost && nodes[n].dist.dominates(d) {
+				return true
+			}
+		}
+		return false
+	}
+
+	queue := &stateQueue{}
+	queue.push(0, s.estimate(base.cell))
+	for queue.Len() > 0 {
+		k, priority := queue.pop()
+		current := nodes[k]
+		if priority > current.cost+s.estimate(current.key.state.cell) { // outdated queue item
+			continue
+		}
+		if isExit[current.key.state.cell] {
+			var states []searchState
+			for n := k; n >= 0; n = nodes[n].parent {
+				states = append([]searchState{nodes[n].key.state}, states...)
+			}
+			path := s.path(states)
+			path.SurvivalChance = current.dist.survival()
+			path.RemainingHP = current.dist.lowestHP()
+			return path, nil
+		}
+		for _, m := range s.moves(current.key.state) {
+			d := s.after(current.dist, m)
+			if d.survival() < minChance-chanceEpsilon {
+				continue
+			}
+			nextCost := current.cost + m.cost
+			if dominated(m.next, d, nextCost) {
+				continue
+			}
+			next := riskState{state: m.next, dist: d.key()}
+			n, visited := index[next]
+			switch {
+			case visited:
+				nodes[n].parent, nodes[n].cost = k, nextCost
+			case s.exhausted(len(nodes)):
+				return nil, ErrStateBudgetExceeded
+			default:
+				n = int32(len(nodes))
+				index[next] = n
+				nodes = append(nodes, riskNode{key: next, dist: d, parent: k, cost: nextCost})
+				seen[m.next] = append(seen[m.next], n)
+			}
+			queue.push(n, nextCost+s.estimate(next.state.cell))
+		}
+	}
+	return nil, ErrNoSurvivablePath
+}
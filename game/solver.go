@@ -0,0 +1,567 @@
+package game
+
+import "errors"
+
+// solver errors
+var (
+	ErrNoStartVertex       = errors.New("start cell does not exist")
+	ErrNoSurvivablePath    = errors.New("no survivable path to any exit")
+	ErrStateBudgetExceeded = errors.New("search state budget exceeded")
+)
+
+// MaxStates is a default max number of states a search may create, see Rules
+const MaxStates = 1000000
+
+// Path is a result of the minimum survivable path search
+type Path struct {
+	Cells       []JI       // cells from the start to the exit, both inclusive
+	Length      int        // number of moves including waits
+	Cost        int        // total move cost in ticks, teleportations may cost more than one
+	Waits       int        // number of ticks the player waits in place for periodic traps
+	Damage      int        // damage taken along the path if every trap hits
+	Healed      int        // HP restored by potions along the path
+	RemainingHP int        // HP left at the exit
+	Traps       []TrapPass // traps passed along the path
+	EnemyHits   []EnemyHit // contacts with enemies along the path
+	Enemies     [][]JI     // cells of each enemy at each step of the path, nil if there are no enemies
+
+	ExpectedDamage float64 // damage taken along the path on average, traps hit with their chances
+	SurvivalChance float64 // probability to reach the exit alive
+}
+
+// bitset is an immutable set of small non-negative integers. Being a string it can be a part of a map key.
+type bitset string
+
+// has returns true if k is in the set
+func (b bitset) has(k int) bool { return k/8 < len(b) && b[k/8]&(1<<uint(k%8)) != 0 }
+
+// with returns a new set containing k and all elements of b
+func (b bitset) with(k int) bitset {
+	bytes := []byte(b)
+	for len(bytes) <= k/8 {
+		bytes = append(bytes, 0)
+	}
+	bytes[k/8] |= 1 << uint(k%8)
+	return bitset(bytes)
+}
+
+// searchState is a cell reached with the given remaining HP, the set of used potions and collected keys.
+// Cell is the index of the cell in the grid. Teleported is true if the cell is a teleporter the player
+// has just arrived to from its partner. Tick is the number of ticks passed modulo the cycle of periodic traps.
+type searchState struct {
+	cell       int32
+	teleported bool
+	hp         int
+	tick       int
+	used       bitset
+	keys       bitset
+}
+
+// searchNode is a search state with the index of the node it is reached from and the move cost of reaching it
+type searchNode struct {
+	state  searchState
+	parent int32 // -1 for the initial state
+	cost   int
+}
+
+// backtrack returns states from the initial one to the one of the node with index k
+func backtrack(nodes []searchNode, k int32) []searchState {
+	var states []searchState
+	for ; k >= 0; k = nodes[k].parent {
+		states = append(states, nodes[k].state)
+	}
+	for a, b := 0, len(states)-1; a < b; a, b = a+1, b-1 {
+		states[a], states[b] = states[b], states[a]
+	}
+	return states
+}
+
+// searchTile contains properties of a tile used by the search
+type searchTile struct {
+	damage int
+	chance float64 // probability of the damage to be dealt
+	heal   int
+	key    int // index of the key color picked up, -1 if none
+	door   int // index of the key color required to enter, -1 if none
+}
+
+// searcher generates states of the game in the grid g
+type searcher struct {
+	g        *Grid
+	tiles    [256]searchTile
+	potions  []int32        // index of the potion at each cell in the used potions set, -1 if none, nil if no potions
+	partners []int32        // index of the partner of each teleporter cell, -1 for other cells, nil if no teleporters
+	colors   map[string]int // index of each key color in the collected keys set
+	cycle    int            // number of ticks after which all periodic traps and enemies repeat
+	budget   int            // max number of states the search may create
+	h        Heuristic      // estimate of the move cost to the nearest exit, nil means zero
+	tracer   SearchTracer   // receives events of the search
+	buf      []move         // moves of the last expanded state, reused to avoid allocations
+}
+
+// newSearcher returns a pointer to a new searcher for the grid g
+func newSearcher(g *Grid) *searcher {
+	s := &searcher{g: g, colors: map[string]int{}, cycle: 1, budget: g.MaxStates, tracer: tracerOr(g.Tracer)}
+	if s.budget <= 0 {
+		s.budget = MaxStates
+	}
+	for _, tile := range g.Palette {
+		for _, color := range []string{tile.Key, tile.Door} {
+			if _, ok := s.colors[color]; !ok && color != "" {
+				s.colors[color] = len(s.colors)
+			}
+		}
+	}
+	color := func(c string) int {
+		if c == "" {
+			return -1
+		}
+		return s.colors[c]
+	}
+	for value := range s.tiles {
+		tile := g.Palette[byte(value)]
+		s.tiles[value] = searchTile{
+			damage: tile.Damage,
+			chance: g.Palette.HitChance(byte(value)),
+			heal:   tile.Heal,
+			key:    color(tile.Key),
+			door:   color(tile.Door),
+		}
+	}
+
+	potions := int32(0)
+	for k, cell := range g.Cells {
+		if s.tiles[cell].heal <= 0 {
+			continue
+		}
+		if s.potions == nil {
+			s.potions = filled(len(g.Cells), -1)
+		}
+		s.potions[k] = potions
+		potions++
+	}
+	if len(g.Teleports) > 0 {
+		s.partners = filled(len(g.Cells), -1)
+		for c, partner := range g.Teleports {
+			s.partners[g.Index(c)] = int32(g.Index(partner))
+		}
+	}
+
+	periods := enemyPeriods(g.Enemies)
+	for _, t := range g.PeriodicTraps {
+		periods = append(periods, t.Period)
+	}
+	s.cycle = cycleOf(periods)
+	return s
+}
+
+// filled returns a slice of the given length filled with the value v
+func filled(length int, v int32) []int32 {
+	res := make([]int32, length)
+	for k := range res {
+		res[k] = v
+	}
+	return res
+}
+
+// potion returns the index of the potion at the cell k in the used potions set, ok is false if there is none
+func (s *searcher) potion(k int32) (n int, ok bool) {
+	if s.potions == nil || s.potions[k] < 0 {
+		return 0, false
+	}
+	return int(s.potions[k]), true
+}
+
+// partner returns the partner of the teleporter at the cell k, ok is false if there is no teleporter
+func (s *searcher) partner(k int32) (partner int32, ok bool) {
+	if s.partners == nil || s.partners[k] < 0 {
+		return 0, false
+	}
+	return s.partners[k], true
+}
+
+// exits returns the set of the given exits indexed by cells
+func (s *searcher) exits(exits []JI) []bool {
+	res := make([]bool, len(s.g.Cells))
+	for _, exit := range exits {
+		res[s.g.Index(exit)] = true
+	}
+	return res
+}
+
+// cell returns coordinates of the cell with index k
+func (s *searcher) cell(k int32) JI { return s.g.Cell(int(k)) }
+
+// estimate returns the heuristic estimate of the move cost from the cell k to the nearest exit
+func (s *searcher) estimate(k int32) int {
+	if s.h == nil {
+		return 0
+	}
+	return s.h(s.cell(k))
+}
+
+// exhausted returns true if the search having created n states can not create one more
+func (s *searcher) exhausted(n int) bool { return n >= s.budget }
+
+// timed returns true if there are periodic traps or enemies, so the player may need to wait
+func (s *searcher) timed() bool { return len(s.g.PeriodicTraps) > 0 || len(s.g.Enemies) > 0 }
+
+// damage taken by the player entering the trap at the cell k on the given tick.
+// Traps are checked on entering only, unlike enemies, see hits.
+func (s *searcher) damage(k int32, tick int) int {
+	if len(s.g.PeriodicTraps) > 0 {
+		if trap, ok := s.g.PeriodicTraps[s.cell(k)]; ok {
+			return trap.damage(s.g.Palette, s.g.Cells[k], tick)
+		}
+	}
+	return s.tiles[s.g.Cells[k]].damage
+}
+
+// hits returns contacts with enemies of the player leaving one cell after the given tick by the move taking
+// cost ticks. The player enters another cell on the next tick and stays there till the end of the move, so slow
+// terrain is waded through. The player contacts enemies being at the same cell on any of these ticks
+// or passing the player in the opposite way on entering the cell.
+func (s *searcher) hits(from JI, tick int, to JI, cost int) (res []EnemyHit) {
+	for t := tick + 1; t <= tick+cost; t++ {
+		for k, e := range s.g.Enemies {
+			if e.at(t) == to || t == tick+1 && from != to && e.at(tick) == to && e.at(t) == from {
+				res = append(res, EnemyHit{Enemy: k, Cell: to, Tick: t, Damage: e.Damage})
+			}
+		}
+	}
+	return
+}
+
+// contact returns the damage taken from enemies by the player moving between the cells with the given indices
+func (s *searcher) contact(from int32, tick int, to int32, cost int) (res int) {
+	if len(s.g.Enemies) == 0 {
+		return 0
+	}
+	for _, hit := range s.hits(s.cell(from), tick, s.cell(to), cost) {
+		res += hit.Damage
+	}
+	return
+}
+
+// cost of the move between the given states in ticks, waiting in place costs one tick
+func (s *searcher) cost(from, to searchState) int {
+	if from.cell == to.cell {
+		return 1
+	}
+	return s.g.Cost(s.cell(from.cell), s.cell(to.cell))
+}
+
+// initial state of the search from the start cell
+func (s *searcher) initial(start JI) searchState {
+	return searchState{cell: int32(s.g.Index(start)), hp: s.g.StartingHP}
+}
+
+// move is a transition to the next state taking cost ticks. HP of the next state is not changed yet, the damage
+// of the trap is dealt with the given chance first, then the contact damage of enemies and then the player is healed.
+type move struct {
+	next    searchState
+	cost    int
+	damage  int
+	chance  float64
+	contact int
+	heal    int
+}
+
+// moves returns transitions from the current state regardless of HP ordered by row and column of target cells.
+// Entering a teleporter forces the jump to its partner, after the jump the player walks off the partner
+// as from an ordinary cell. If there are periodic traps or enemies the player may also wait in place for one tick.
+// The returned slice is reused by the next call.
+func (s *searcher) moves(current searchState) []move {
+	res := s.buf[:0]
+	partner, isTeleporter := s.partner(current.cell)
+	to, costs := s.g.Arcs(int(current.cell))
+	for n, c := range to {
+		jump := isTeleporter && c == partner
+		if isTeleporter && jump == current.teleported {
+			continue
+		}
+		tile := &s.tiles[s.g.Cells[c]]
+		if tile.door >= 0 && !current.keys.has(tile.door) { // the door is locked
+			continue
+		}
+		cost := int(costs[n])
+		m := move{
+			next: searchState{
+				cell:       c,
+				teleported: jump,
+				hp:         current.hp,
+				tick:       (current.tick + cost) % s.cycle,
+				used:       current.used,
+				keys:       current.keys,
+			},
+			cost:   cost,
+			chance: tile.chance,
+		}
+		// the trap is entered on the first tick of the move and is not checked on the rest of its ticks
+		m.damage = s.damage(c, current.tick+1)
+		m.contact = s.contact(current.cell, current.tick, c, cost)
+		if tile.key >= 0 && !current.keys.has(tile.key) {
+			m.next.keys = current.keys.with(tile.key)
+		}
+		// the potion is left for later if it restores nothing, otherwise the number of states
+		// grows exponentially with the number of potions passed with the full HP
+		hp := current.hp - m.damage - m.contact
+		if k, isPotion := s.potion(c); isPotion && !current.used.has(k) && s.healed(hp, tile.heal) > hp {
+			m.heal = tile.heal
+			m.next.used = current.used.with(k)
+		}
+		res = append(res, m)
+	}
+
+	if s.timed() && (!isTeleporter || current.teleported) {
+		wait := current
+		wait.tick = (current.tick + 1) % s.cycle
+		res = append(res, move{next: wait, cost: 1, contact: s.contact(wait.cell, current.tick, wait.cell, 1)})
+	}
+	s.buf = res
+	return res
+}
+
+// healed returns HP restored by heal, but not above the starting HP
+func (s *searcher) healed(hp, heal int) int {
+	if hp += heal; hp > s.g.StartingHP {
+		return s.g.StartingHP
+	}
+	return hp
+}
+
+// next returns moves to states reachable alive by one move from the current state with HP of the states updated.
+// Damage is considered to be dealt always regardless of its chance. The returned slice is reused by the next call.
+func (s *searcher) next(current searchState) []move {
+	moves := s.moves(current)
+	res := moves[:0]
+	for _, m := range moves {
+		if m.next.hp -= m.damage + m.contact; m.next.hp <= 0 { // player dies here
+			s.tracer.Prune(s.cell(m.next.cell), m.next.hp, PruneDead)
+			continue
+		}
+		if m.next.hp = s.healed(m.next.hp, m.heal); m.next.hp != current.hp {
+			s.tracer.HPChange(s.cell(m.next.cell), current.hp, m.next.hp)
+		}
+		res = append(res, m)
+	}
+	return res
+}
+
+// explore all states reachable alive from the start cell.
+// It returns the nodes of the states and the moves between them as pairs of node indices,
+// ErrStateBudgetExceeded if there are more states than the budget allows.
+func (s *searcher) explore(start JI) (nodes []searchNode, arcs [][2]int32, err error) {
+	initial := s.initial(start)
+	nodes = []searchNode{{state: initial, parent: -1}}
+	index := map[searchState]int32{initial: 0}
+	for current := int32(0); int(current) < len(nodes); current++ {
+		for _, m := range s.next(nodes[current].state) {
+			k, visited := index[m.next]
+			if !visited {
+				if s.exhausted(len(nodes)) {
+					return nil, nil, ErrStateBudgetExceeded
+				}
+				k = int32(len(nodes))
+				index[m.next] = k
+				nodes = append(nodes, searchNode{state: m.next, parent: current})
+			}
+			arcs = append(arcs, [2]int32{current, k})
+		}
+	}
+	return
+}
+
+// reversed returns the given arcs between n vertices reversed and grouped by their targets:
+// vertices having arcs to the vertex k are from[first[k]:first[k+1]]
+func reversed(n int, arcs [][2]int32) (first, from []int32) {
+	first = make([]int32, n+1)
+	for _, arc := range arcs {
+		first[arc[1]+1]++
+	}
+	for k := 0; k < n; k++ {
+		first[k+1] += first[k]
+	}
+	from = make([]int32, len(arcs))
+	next := append([]int32(nil), first[:n]...)
+	for _, arc := range arcs {
+		from[next[arc[1]]] = arc[0]
+		next[arc[1]]++
+	}
+	return
+}
+
+// MinSurvivablePath searches for the minimum survivable path in the grid g from start to the nearest of exits.
+// The search runs over (cell, remaining HP, used potions, collected keys) states, so a longer path arriving
+// with more HP is not blocked by a shorter one arriving damaged. Paths are compared by the total move cost,
+// which equals the number of moves unless tiles or teleportations cost more. The grid is not modified.
+// ErrStateBudgetExceeded is returned if the search creates more states than MaxStates of the grid allows.
+func MinSurvivablePath(g *Grid, start JI, exits []JI) (*Path, error) {
+	return minSurvivablePath(g, start, exits, nil)
+}
+
+// minSurvivablePath searches for the minimum survivable path expanding states in order of their move cost
+// plus the estimate h of the rest of the way, nil h means zero estimate
+func minSurvivablePath(g *Grid, start JI, exits []JI, h Heuristic) (*Path, error) {
+	if !g.Has(start) {
+		return nil, ErrNoStartVertex
+	}
+	s := newSearcher(g)
+	s.h = h
+	isExit := s.exits(exits)
+	initial := s.initial(start)
+	nodes := []searchNode{{state: initial, parent: -1}}
+	index := map[searchState]int32{initial: 0}
+	queue := &stateQueue{}
+	queue.push(0, s.estimate(initial.cell))
+	s.tracer.Enqueue(start, initial.hp, 0)
+	for queue.Len() > 0 {
+		k, priority := queue.pop()
+		current := nodes[k]
+		if priority > current.cost+s.estimate(current.state.cell) { // outdated queue item
+			continue
+		}
+		s.tracer.Visit(s.cell(current.state.cell), current.state.hp, current.cost)
+		if isExit[current.state.cell] {
+			return s.survivablePath(backtrack(nodes, k)), nil
+		}
+
+		for _, m := range s.next(current.state) {
+			next, nextCost := m.next, current.cost+m.cost
+			n, visited := index[next]
+			switch {
+			case visited && nodes[n].cost <= nextCost:
+				s.tracer.Prune(s.cell(next.cell), next.hp, PruneCostlier)
+				continue
+			case visited:
+				nodes[n].parent, nodes[n].cost = k, nextCost
+			case s.exhausted(len(nodes)):
+				return nil, ErrStateBudgetExceeded
+			default:
+				n = int32(len(nodes))
+				index[next] = n
+				nodes = append(nodes, searchNode{state: next, parent: k, cost: nextCost})
+			}
+			queue.push(n, nextCost+s.estimate(next.cell))
+			s.tracer.Enqueue(s.cell(next.cell), next.hp, nextCost)
+		}
+	}
+	return nil, ErrNoSurvivablePath
+}
+
+// queueItem is an index of a search node queued with its cost, which is the move cost of the node plus
+// the estimate of the rest of the way for A* search. Seq preserves the order of pushing among equal costs,
+// so the search with unit costs and without estimates visits states in the same order as the breadth-first one.
+type queueItem struct {
+	node int32
+	cost int
+	seq  int
+}
+
+// stateQueue is a priority queue of indices of search nodes of any type ordered by cost, it is a binary heap
+// kept without container/heap to avoid allocating an interface value for each item
+type stateQueue struct {
+	items []queueItem
+	seq   int
+}
+
+// Len returns the number of queued items
+func (q *stateQueue) Len() int { return len(q.items) }
+
+// less returns true if the item a should be popped before the item b
+func (q *stateQueue) less(a, b int) bool {
+	if q.items[a].cost != q.items[b].cost {
+		return q.items[a].cost < q.items[b].cost
+	}
+	return q.items[a].seq < q.items[b].seq
+}
+
+// push the node with the given cost to the queue
+func (q *stateQueue) push(node int32, cost int) {
+	q.items = append(q.items, queueItem{node: node, cost: cost, seq: q.seq})
+	q.seq++
+	for k := len(q.items) - 1; k > 0; {
+		parent := (k - 1) / 2
+		if !q.less(k, parent) {
+			break
+		}
+		q.items[k], q.items[parent] = q.items[parent], q.items[k]
+		k = parent
+	}
+}
+
+// pop the node with the least cost from the queue
+func (q *stateQueue) pop() (int32, int) {
+	top := q.items[0]
+	last := len(q.items) - 1
+	q.items[0] = q.items[last]
+	q.items = q.items[:last]
+	for k := 0; ; {
+		least := k
+		for _, child := range []int{2*k + 1, 2*k + 2} {
+			if child < len(q.items) && q.less(child, least) {
+				least = child
+			}
+		}
+		if least == k {
+			break
+		}
+		q.items[k], q.items[least] = q.items[least], q.items[k]
+		k = least
+	}
+	return top.node, top.cost
+}
+
+// survivablePath returns the path passing the given states with damage always dealt
+func (s *searcher) survivablePath(states []searchState) *Path {
+	path := s.path(states)
+	path.RemainingHP, path.SurvivalChance = states[len(states)-1].hp, 1
+	path.Healed = path.RemainingHP - s.g.StartingHP + path.Damage
+	return path
+}
+
+// path returns the path passing the given states from the initial to the final one.
+// HP related fields of the path are not filled.
+func (s *searcher) path(states []searchState) *Path {
+	path := &Path{
+		Cells:  make([]JI, len(states)),
+		Length: len(states) - 1,
+	}
+	for k, state := range states {
+		path.Cells[k] = s.cell(state.cell)
+		prevTick := path.Cost
+		if k > 0 {
+			path.Cost += s.cost(states[k-1], state)
+		}
+		if len(s.g.Enemies) > 0 {
+			enemies := make([]JI, len(s.g.Enemies))
+			for n, e := range s.g.Enemies {
+				enemies[n] = e.at(path.Cost)
+			}
+			path.Enemies = append(path.Enemies, enemies)
+		}
+		if k == 0 {
+			continue
+		}
+
+		from, to := path.Cells[k-1], path.Cells[k]
+		hits := s.hits(from, prevTick, to, path.Cost-prevTick)
+		path.EnemyHits = append(path.EnemyHits, hits...)
+		for _, hit := range hits {
+			path.Damage += hit.Damage
+			path.ExpectedDamage += float64(hit.Damage)
+		}
+		if from == to {
+			path.Waits++
+			continue
+		}
+		value := s.g.Cells[state.cell]
+		damage := s.damage(state.cell, prevTick+1)
+		path.Damage += damage
+		path.ExpectedDamage += float64(damage) * s.tiles[value].chance
+		if _, periodic := s.g.PeriodicTraps[to]; periodic || s.tiles[value].damage > 0 {
+			path.Traps = append(path.Traps, TrapPass{Cell: to, Tick: prevTick + 1, Damage: damage})
+		}
+	}
+	return path
+}
@@ -0,0 +1,73 @@
+package game_test
+
+import (
+	"testing"
+
+	"github.com/mtfelian/gjg-test-task/game"
+)
+
+// benchmarkPosition returns a square position of the given size with walls having gaps every few rows,
+// pits scattered around, the start in the top left corner and the exit tile in the bottom right one
+func benchmarkPosition(size int) game.Position {
+	rules := game.DefaultRules()
+	rules.ExitMode, rules.MaxDim, rules.MaxStates = game.ExitModeTile, size, size*size*rules.StartingHP
+	p := game.Position{Maze: make([][]byte, size), Rules: rules}
+	for i := range p.Maze {
+		p.Maze[i] = make([]byte, size)
+		for j := range p.Maze[i] {
+			switch {
+			case j%4 == 2 && i%8 != j%8:
+				p.Maze[i][j] = game.CellWall
+			case (i*7+j*13)%17 == 0:
+				p.Maze[i][j] = game.CellPit
+			}
+		}
+	}
+	p.Maze[0][0], p.Maze[size-1][size-1] = game.CellPlayer, game.CellExit
+	return p
+}
+
+func benchmarkSolve(b *testing.B, size int, solve func(game.Position) (*game.Path, *game.Error)) {
+	p := benchmarkPosition(size)
+	if Err := p.Validate(); Err != nil {
+		b.Fatal(Err.Message)
+	}
+	b.ReportAllocs()
+	b.ResetTimer()
+	for n := 0; n < b.N; n++ {
+		if _, Err := solve(p); Err != nil {
+			b.Fatal(Err.Message)
+		}
+	}
+}
+
+func BenchmarkSolveBFS100(b *testing.B)    { benchmarkSolve(b, 100, game.Position.Solve) }
+func BenchmarkSolveAStar100(b *testing.B)  { benchmarkSolve(b, 100, game.Position.SolveAStar) }
+func BenchmarkSolveBFS300(b *testing.B)    { benchmarkSolve(b, 300, game.Position.Solve) }
+func BenchmarkSolveAStar300(b *testing.B)  { benchmarkSolve(b, 300, game.Position.SolveAStar) }
+func BenchmarkSolveAStar1000(b *testing.B) { benchmarkSolve(b, 1000, game.Position.SolveAStar) }
+
+func BenchmarkToGrid100(b *testing.B) {
+	p := benchmarkPosition(100)
+	b.ReportAllocs()
+	b.ResetTimer()
+	for n := 0; n < b.N; n++ {
+		if _, err := p.ToGrid(); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
+
+func benchmarkLint(b *testing.B, size int) {
+	p := benchmarkPosition(size)
+	b.ReportAllocs()
+	b.ResetTimer()
+	for n := 0; n < b.N; n++ {
+		p.Lint()
+	}
+}
+
+func BenchmarkLint100(b *testing.B) { benchmarkLint(b, 100) }
+
+// survivable paths are not checked by default at this size
+func BenchmarkLint1000(b *testing.B) { benchmarkLint(b, 1000) }
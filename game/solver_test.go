@@ -0,0 +1,746 @@
+package game_test
+
+import (
+	"bytes"
+	"math/rand"
+	"time"
+
+	"github.com/mtfelian/gjg-test-task/game"
+	"github.com/mtfelian/gjg-test-task/service"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("minimum survivable path", func() {
+	readmeMaze := func() [][]byte {
+		return [][]byte{
+			{1, 1, 1, 1, 0, 1, 1, 1},
+			{1, 0, 0, 0, 0, 0, 0, 1},
+			{1, 0, 1, 1, 1, 3, 1, 1},
+			{1, 0, 0, 0, 1, 0, 2, 1},
+			{1, 1, 1, 0, 1, 1, 0, 1},
+			{1, 0, 0, 0, 1, 0, 0, 1},
+			{1, 0, 1, 1, 1, 0, 1, 1},
+			{1, 0, 0, 4, 0, 0, 0, 1},
+			{1, 1, 1, 1, 1, 1, 1, 1},
+		}
+	}
+
+	solve := func(p game.Position) (*game.Path, *game.Grid, error) {
+		grid, err := p.ToGrid()
+		Expect(err).NotTo(HaveOccurred())
+		start, ok := p.Start()
+		Expect(ok).To(BeTrue())
+		path, err := game.MinSurvivablePath(grid, start, p.Exits())
+		return path, grid, err
+	}
+
+	It("checks the README example: 12 moves path with 3 damage is chosen", func() {
+		path, _, err := solve(game.Position{Maze: readmeMaze()})
+		Expect(err).NotTo(HaveOccurred())
+		Expect(path.Length).To(Equal(12))
+		Expect(path.Damage).To(Equal(3))
+		Expect(path.RemainingHP).To(Equal(1))
+		Expect(path.Cells).To(HaveLen(13))
+		Expect(path.Cells[0]).To(Equal(game.JI{J: 3, I: 7}))
+		Expect(path.Cells[12]).To(Equal(game.JI{J: 4, I: 0}))
+	})
+
+	It("checks the README example with an arrow trap instead of the pit: 16 moves path is chosen", func() {
+		maze := readmeMaze()
+		maze[3][6] = game.CellArrow
+		path, _, err := solve(game.Position{Maze: maze})
+		Expect(err).NotTo(HaveOccurred())
+		Expect(path.Length).To(Equal(16))
+		Expect(path.Damage).To(Equal(0))
+		Expect(path.RemainingHP).To(Equal(4))
+	})
+
+	It("checks that a short damaged path does not block a longer healthy one", func() {
+		p := game.Position{Maze: [][]byte{
+			{1, 1, 1, 1, 1, 1, 1},
+			{1, 0, 0, 0, 0, 0, 1},
+			{1, 0, 1, 1, 1, 0, 1},
+			{1, 4, 3, 2, 0, 0, 1},
+			{1, 1, 1, 1, 1, 2, 1},
+			{1, 1, 1, 1, 1, 0, 1},
+			{1, 1, 1, 1, 1, 0, 1},
+		}}
+		path, grid, err := solve(p)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(path.Length).To(Equal(11))
+		Expect(path.Damage).To(Equal(1))
+		Expect(path.Cells[len(path.Cells)-1]).To(Equal(game.JI{J: 5, I: 6}))
+
+		By("checking that the grid is not modified", func() {
+			expected, err := p.ToGrid()
+			Expect(err).NotTo(HaveOccurred())
+			Expect(grid).To(Equal(expected))
+		})
+	})
+
+	It("checks that the README example has the fast and the safe routes", func() {
+		paths, Err := game.Position{Maze: readmeMaze()}.Routes()
+		Expect(Err).To(BeNil())
+		Expect(paths).To(HaveLen(2))
+		Expect(paths[0].Length).To(Equal(12))
+		Expect(paths[0].Damage).To(Equal(3))
+		Expect(paths[0].Cells).To(HaveLen(13))
+		Expect(paths[1].Length).To(Equal(16))
+		Expect(paths[1].Damage).To(Equal(0))
+		Expect(paths[1].RemainingHP).To(Equal(4))
+		Expect(paths[1].Cells[16]).To(Equal(game.JI{J: 4, I: 0}))
+
+		By("checking that the only route is returned if it is both the fastest and the safest", func() {
+			maze := readmeMaze()
+			maze[2][5] = game.CellOpen
+			maze[3][6] = game.CellOpen
+			paths, Err = game.Position{Maze: maze}.Routes()
+			Expect(Err).To(BeNil())
+			Expect(paths).To(HaveLen(1))
+			Expect(paths[0].Length).To(Equal(12))
+			Expect(paths[0].Damage).To(Equal(0))
+		})
+
+		By("checking that absence of survivable routes is reported", func() {
+			_, Err = game.Position{Maze: [][]byte{{1, 1, 1, 1}, {1, 4, 3, 3}, {1, 3, 1, 0}}}.Routes()
+			Expect(Err).NotTo(BeNil())
+			Expect(Err.Code).To(Equal(service.ErrNoSurvivablePath))
+		})
+	})
+
+	It("checks the minimum starting HP and the HP curve", func() {
+		analysis, Err := game.Position{Maze: readmeMaze()}.AnalyzeHP()
+		Expect(Err).To(BeNil())
+		Expect(analysis.MinStartingHP).To(Equal(1))
+		Expect(analysis.Curve).To(Equal([]game.HPCurvePoint{
+			{StartingHP: 1, Solvable: true, Length: 16, Cost: 16},
+			{StartingHP: 2, Solvable: true, Length: 16, Cost: 16},
+			{StartingHP: 3, Solvable: true, Length: 16, Cost: 16},
+			{StartingHP: 4, Solvable: true, Length: 12, Cost: 12, Damage: 3},
+		}))
+
+		analysis, Err = game.Position{Maze: [][]byte{{1, 1, 1, 1, 1, 1}, {1, 4, 3, 2, 3, 0}, {1, 1, 1, 1, 1, 1}}}.AnalyzeHP()
+		Expect(Err).To(BeNil())
+		Expect(analysis.MinStartingHP).To(Equal(6))
+		Expect(analysis.Curve).To(HaveLen(6))
+		Expect(analysis.Curve[4].Solvable).To(BeFalse())
+		Expect(analysis.Curve[5]).To(Equal(game.HPCurvePoint{StartingHP: 6, Solvable: true, Length: 4, Cost: 4, Damage: 5}))
+
+		_, Err = game.Position{Maze: [][]byte{{1, 1, 1}, {1, 4, 1}, {1, 1, 1}}}.AnalyzeHP()
+		Expect(Err).NotTo(BeNil())
+		Expect(Err.Code).To(Equal(service.ErrLevelHasNoExit))
+	})
+
+	It("checks solving a position restored from the storage format", func() {
+		p := game.Position{X: 8, Y: 9, Maze: readmeMaze()}
+		restored := game.FromStorage(p.ToStorage())
+		Expect(restored).To(Equal(p))
+
+		path, Err := restored.Solve()
+		Expect(Err).To(BeNil())
+		Expect(path.Length).To(Equal(12))
+	})
+
+	It("checks typed errors of solving positions without start, exits or survivable path", func() {
+		type tcs struct {
+			maze         [][]byte
+			expectedCode int
+		}
+		for i, tc := range []tcs{
+			{maze: [][]byte{{1, 0, 1}, {1, 0, 1}}, expectedCode: service.ErrLevelHasNoStart},
+			{maze: [][]byte{{1, 1, 1}, {1, 4, 1}, {1, 1, 1}}, expectedCode: service.ErrLevelHasNoExit},
+			{maze: [][]byte{{1, 1, 1, 1}, {1, 4, 3, 3}, {1, 3, 1, 0}}, expectedCode: service.ErrNoSurvivablePath},
+		} {
+			path, Err := game.Position{Maze: tc.maze}.Solve()
+			Expect(path).To(BeNil(), "case %d", i)
+			Expect(Err).NotTo(BeNil(), "case %d", i)
+			Expect(Err.Code).To(Equal(tc.expectedCode), "case %d", i)
+		}
+	})
+
+	It("checks that the start of the position without survivable path is reported as (row,column)", func() {
+		_, Err := game.Position{Maze: [][]byte{
+			{1, 1, 1, 1, 1},
+			{0, 3, 3, 4, 1},
+			{1, 1, 1, 1, 1},
+		}}.Solve()
+		Expect(Err).NotTo(BeNil())
+		Expect(Err.Code).To(Equal(service.ErrNoSurvivablePath))
+		Expect(Err.Params).To(Equal([]interface{}{1, 3}))
+		Expect(Err.Message).To(Equal("There is no survivable path from (1,3) to any exit"))
+	})
+
+	It("checks that healing potions restore HP up to the starting HP once per path", func() {
+		type tcs struct {
+			row                                 []byte
+			length, damage, healed, remainingHP int
+			solvable                            bool
+		}
+		for i, tc := range []tcs{
+			{row: []byte{1, 4, 3, 5, 3, 0}, length: 4, damage: 4, healed: 2, remainingHP: 2, solvable: true},
+			{row: []byte{1, 4, 3, 5, 3, 5, 3, 0}, length: 6, damage: 6, healed: 4, remainingHP: 2, solvable: true},
+			{row: []byte{1, 4, 5, 3, 3, 0}}, // potion restores nothing at full HP
+			{row: []byte{1, 4, 3, 3, 0}},
+		} {
+			walls := bytes.Repeat([]byte{1}, len(tc.row))
+			p := game.Position{Maze: [][]byte{walls, tc.row, walls}}
+			Expect(p.Validate()).To(BeNil(), "case %d", i)
+			path, Err := p.Solve()
+			if !tc.solvable {
+				Expect(Err).NotTo(BeNil(), "case %d", i)
+				Expect(Err.Code).To(Equal(service.ErrNoSurvivablePath), "case %d", i)
+				continue
+			}
+			Expect(Err).To(BeNil(), "case %d", i)
+			Expect(path.Length).To(Equal(tc.length), "case %d", i)
+			Expect(path.Damage).To(Equal(tc.damage), "case %d", i)
+			Expect(path.Healed).To(Equal(tc.healed), "case %d", i)
+			Expect(path.RemainingHP).To(Equal(tc.remainingHP), "case %d", i)
+		}
+	})
+
+	It("checks that a room full of potions is solved and linted in time and too many potions are rejected", func() {
+		p := game.Position{Maze: make([][]byte, 14)}
+		for i := range p.Maze {
+			p.Maze[i] = make([]byte, 14)
+			for j := range p.Maze[i] {
+				if i == 0 || j == 0 || i == 13 || j == 13 {
+					p.Maze[i][j] = game.CellWall
+				}
+			}
+		}
+		p.Maze[1][1], p.Maze[13][12] = game.CellPlayer, game.CellOpen
+		p.Maze[6][6], p.Maze[8][3], p.Maze[3][9] = game.CellPit, game.CellArrow, game.CellPit
+		for k := 0; k < game.MaxPotions; k++ {
+			p.Maze[2+k%10][2+(k*7)%10] = game.CellPotion
+		}
+		Expect(p.Validate()).To(BeNil())
+
+		started := time.Now()
+		path, Err := p.Solve()
+		Expect(Err).To(BeNil())
+		Expect(path.Healed).To(BeZero()) // potions are not consumed at full HP
+		p.Lint()
+		Expect(time.Since(started)).To(BeNumerically("<", 5*time.Second))
+
+		p.Maze[12][2] = game.CellPotion
+		Err = p.Validate()
+		Expect(Err).NotTo(BeNil())
+		Expect(Err.Code).To(Equal(service.ErrValidationTooManyPotions))
+		Expect(Err.Params).To(Equal([]interface{}{game.MaxPotions + 1, game.MaxPotions}))
+	})
+
+	It("checks that potions of a level of the max size are limited by the state budget", func() {
+		// the field of pits is separated from the exit by a corridor of arrows dealing more damage than the HP
+		p := game.Position{Maze: make([][]byte, game.MaxDim)}
+		for i := range p.Maze {
+			p.Maze[i] = make([]byte, game.MaxDim)
+			for j := range p.Maze[i] {
+				switch {
+				case i == 0 || j == 0 || i == game.MaxDim-1 || j == game.MaxDim-1:
+					p.Maze[i][j] = game.CellWall
+				case i <= 3:
+					p.Maze[i][j] = game.CellWall
+					if j == game.MaxDim/2 {
+						p.Maze[i][j] = game.CellArrow
+					}
+				case (i+j)%7 == 0:
+					p.Maze[i][j] = game.CellPit
+				}
+			}
+		}
+		p.Maze[0][game.MaxDim/2] = game.CellExit
+		p.Maze[game.MaxDim/2][game.MaxDim/2] = game.CellPlayer
+		for k := 0; k < game.MaxPotions; k++ {
+			p.Maze[10+k*13][5+k*15] = game.CellPotion
+		}
+		Err := p.Validate()
+		Expect(Err).NotTo(BeNil())
+		Expect(Err.Code).To(Equal(service.ErrValidationTooManyPotions))
+		Expect(Err.Params).To(Equal([]interface{}{game.MaxPotions, 4}))
+
+		p.Maze[10][5], p.Maze[23][20] = game.CellOpen, game.CellOpen
+		Expect(p.Validate()).To(BeNil())
+		started := time.Now()
+		_, Err = p.Solve()
+		Expect(Err).NotTo(BeNil())
+		Expect(Err.Code).To(Equal(service.ErrNoSurvivablePath))
+		p.Lint()
+		Expect(time.Since(started)).To(BeNumerically("<", 5*time.Second))
+	})
+
+	It("checks that searches stop on exceeding the state budget", func() {
+		p := game.Position{Maze: [][]byte{
+			{1, 1, 1, 1, 1, 1},
+			{1, 4, 0, 2, 0, 0},
+			{1, 1, 1, 1, 1, 1},
+		}, Rules: game.DefaultRules()}
+		p.Rules.MaxStates = 3
+		for name, solve := range map[string]func() (*game.Path, *game.Error){
+			"Solve":                p.Solve,
+			"SolveAStar":           p.SolveAStar,
+			"SolveWithChance":      func() (*game.Path, *game.Error) { return p.SolveWithChance(0.5) },
+			"SolveAStarWithChance": func() (*game.Path, *game.Error) { return p.SolveAStarWithChance(0.5) },
+			"Routes": func() (*game.Path, *game.Error) {
+				_, Err := p.Routes()
+				return nil, Err
+			},
+		} {
+			_, Err := solve()
+			Expect(Err).NotTo(BeNil(), name)
+			Expect(Err.Code).To(Equal(service.ErrSolverBudgetExceeded), name)
+		}
+
+		p.Rules.MaxStates = 0
+		path, Err := p.Solve()
+		Expect(Err).To(BeNil())
+		Expect(path.Length).To(Equal(4))
+	})
+
+	It("checks that a detour to a potion is taken when the direct path is deadly", func() {
+		p := game.Position{Maze: [][]byte{
+			{1, 1, 1, 1, 1, 1, 1},
+			{1, 1, 1, 5, 1, 1, 1},
+			{1, 1, 1, 0, 1, 1, 1},
+			{1, 4, 3, 0, 3, 2, 0},
+			{1, 1, 1, 1, 1, 1, 1},
+		}}
+		path, Err := p.Solve()
+		Expect(Err).To(BeNil())
+		Expect(path.Length).To(Equal(9))
+		Expect(path.Damage).To(Equal(5))
+		Expect(path.Healed).To(Equal(2))
+		Expect(path.RemainingHP).To(Equal(1))
+		Expect(path.Cells[4]).To(Equal(game.JI{J: 3, I: 1}))
+	})
+
+	It("checks that a locked door requires the key of the same color collected on the path", func() {
+		p := game.Position{Maze: [][]byte{
+			{1, 1, 1, 1, 1, 1, 1},
+			{1, 6, 0, 1, 1, 1, 1},
+			{1, 1, 0, 1, 1, 1, 1},
+			{1, 4, 0, 0, 0, 9, 0},
+			{1, 1, 1, 1, 1, 1, 1},
+		}}
+		Expect(p.Validate()).To(BeNil())
+		path, Err := p.Solve()
+		Expect(Err).To(BeNil())
+		Expect(path.Length).To(Equal(11))
+		Expect(path.Cells[4]).To(Equal(game.JI{J: 1, I: 1}))
+
+		By("checking that a key of another color does not open the door", func() {
+			p.Maze[1][1] = game.CellKeyGreen
+			_, Err = p.Solve()
+			Expect(Err).NotTo(BeNil())
+			Expect(Err.Code).To(Equal(service.ErrNoSurvivablePath))
+		})
+	})
+
+	It("checks that entering a teleporter moves the player to its partner", func() {
+		p := game.Position{
+			Maze: [][]byte{
+				{1, 1, 1, 1, 1, 1, 1},
+				{1, 4, 12, 1, 12, 0, 0},
+				{1, 0, 1, 1, 1, 1, 1},
+				{1, 0, 0, 0, 0, 0, 0},
+				{1, 1, 1, 1, 1, 1, 1},
+			},
+			Teleporters: []game.Teleporter{{A: game.JI{J: 2, I: 1}, B: game.JI{J: 4, I: 1}}},
+		}
+		Expect(p.Validate()).To(BeNil())
+		path, Err := p.Solve()
+		Expect(Err).To(BeNil())
+		Expect(path.Length).To(Equal(4))
+		Expect(path.Cost).To(Equal(4))
+		Expect(path.Cells).To(Equal([]game.JI{{J: 1, I: 1}, {J: 2, I: 1}, {J: 4, I: 1}, {J: 5, I: 1}, {J: 6, I: 1}}))
+
+		By("checking that an expensive teleportation is not taken", func() {
+			p.Teleporters[0].Cost = 5
+			path, Err = p.Solve()
+			Expect(Err).To(BeNil())
+			Expect(path.Length).To(Equal(7))
+			Expect(path.Cost).To(Equal(7))
+			Expect(path.Cells[len(path.Cells)-1]).To(Equal(game.JI{J: 6, I: 3}))
+		})
+
+		By("checking that the teleportation cost is configurable", func() {
+			p.Teleporters[0].Cost = 0
+			p.Rules = game.DefaultRules()
+			p.Rules.TeleportCost = 2
+			path, Err = p.Solve()
+			Expect(Err).To(BeNil())
+			Expect(path.Length).To(Equal(4))
+			Expect(path.Cost).To(Equal(5))
+		})
+	})
+
+	It("checks that the player cannot walk past a teleporter without the teleportation", func() {
+		p := game.Position{
+			X: 5,
+			Y: 5,
+			Maze: [][]byte{
+				{1, 1, 1, 1, 1},
+				{1, 4, 12, 0, 0},
+				{1, 1, 1, 1, 1},
+				{1, 12, 1, 1, 1},
+				{1, 1, 1, 1, 1},
+			},
+			Teleporters: []game.Teleporter{{A: game.JI{J: 2, I: 1}, B: game.JI{J: 1, I: 3}}},
+		}
+		Expect(p.Validate()).To(BeNil())
+		_, Err := p.Solve()
+		Expect(Err).NotTo(BeNil())
+		Expect(Err.Code).To(Equal(service.ErrNoSurvivablePath))
+
+		By("checking that teleporters are restored from the storage format", func() {
+			Expect(game.FromStorage(p.ToStorage())).To(Equal(p))
+		})
+	})
+
+	It("checks that a conveyor can be left only in its direction", func() {
+		p := game.Position{Maze: [][]byte{
+			{1, 1, 1, 1},
+			{0, game.CellConveyorLeft, 4, 1},
+			{1, 1, 1, 1},
+		}}
+		Expect(p.Validate()).To(BeNil())
+		path, Err := p.Solve()
+		Expect(Err).To(BeNil())
+		Expect(path.Length).To(Equal(2))
+
+		p.Maze[1][1] = game.CellConveyorRight
+		_, Err = p.Solve()
+		Expect(Err).NotTo(BeNil())
+		Expect(Err.Code).To(Equal(service.ErrNoSurvivablePath))
+	})
+
+	It("checks that the player waits for a periodic trap to become inactive", func() {
+		trap := game.PeriodicTrap{Cell: game.JI{J: 2, I: 1}, Period: 3, Phase: 2, Active: 2, Damage: 4}
+		p := game.Position{
+			Maze: [][]byte{
+				{1, 1, 1, 1},
+				{1, 4, 3, 0},
+				{1, 1, 1, 1},
+			},
+			PeriodicTraps: []game.PeriodicTrap{trap},
+		}
+		Expect(p.Validate()).To(BeNil())
+		path, Err := p.Solve()
+		Expect(Err).To(BeNil())
+		Expect(path.Length).To(Equal(4))
+		Expect(path.Cost).To(Equal(4))
+		Expect(path.Waits).To(Equal(2))
+		Expect(path.Damage).To(Equal(0))
+		Expect(path.RemainingHP).To(Equal(4))
+		Expect(path.Cells).To(Equal([]game.JI{{J: 1, I: 1}, {J: 1, I: 1}, {J: 1, I: 1}, {J: 2, I: 1}, {J: 3, I: 1}}))
+		Expect(path.Traps).To(Equal([]game.TrapPass{{Cell: trap.Cell, Tick: 3, Damage: 0}}))
+
+		By("checking that the trap is passed without waiting if it is survivable", func() {
+			p.PeriodicTraps[0].Damage = 0
+			path, Err = p.Solve()
+			Expect(Err).To(BeNil())
+			Expect(path.Length).To(Equal(2))
+			Expect(path.Waits).To(Equal(0))
+			Expect(path.Traps).To(Equal([]game.TrapPass{{Cell: trap.Cell, Tick: 1, Damage: 2}}))
+		})
+
+		By("checking that periodic traps are restored from the storage format", func() {
+			p.X, p.Y = 4, 3
+			Expect(game.FromStorage(p.ToStorage())).To(Equal(p))
+		})
+	})
+
+	It("checks that random traps are avoided unless the chance of survival is enough", func() {
+		p := game.Position{Maze: [][]byte{
+			{1, 1, 1, 1, 1, 1},
+			{1, 4, game.CellRandomTrap, game.CellRandomTrap, 0, 0},
+			{1, 0, 1, 1, 0, 1},
+			{1, 0, 0, 0, 0, 1},
+			{1, 1, 1, 1, 1, 1},
+		}}
+		Expect(p.Validate()).To(BeNil())
+		path, Err := p.Solve()
+		Expect(Err).To(BeNil())
+		Expect(path.Length).To(Equal(8))
+		Expect(path.SurvivalChance).To(Equal(1.))
+
+		path, Err = p.SolveWithChance(0.7)
+		Expect(Err).To(BeNil())
+		Expect(path.Length).To(Equal(4))
+		Expect(path.Damage).To(Equal(4))
+		Expect(path.ExpectedDamage).To(BeNumerically("~", 2))
+		Expect(path.SurvivalChance).To(BeNumerically("~", 0.75))
+		Expect(path.RemainingHP).To(Equal(2))
+
+		path, Err = p.SolveWithChance(0.8)
+		Expect(Err).To(BeNil())
+		Expect(path.Length).To(Equal(8))
+		Expect(path.SurvivalChance).To(BeNumerically("~", 1))
+		Expect(path.RemainingHP).To(Equal(4))
+
+		p.Maze[2][4] = game.CellWall
+		_, Err = p.SolveWithChance(0.8)
+		Expect(Err).NotTo(BeNil())
+		Expect(Err.Code).To(Equal(service.ErrNoSurvivablePath))
+	})
+
+	It("checks that the player avoids or absorbs hits of a patrolling enemy", func() {
+		enemy := game.Enemy{Route: []game.JI{{J: 3, I: 2}, {J: 3, I: 1}}, Rate: 1, Damage: 4}
+		p := game.Position{
+			Maze: [][]byte{
+				{1, 1, 1, 1, 1, 1},
+				{1, 1, 4, 0, 0, 0},
+				{1, 1, 1, 0, 1, 1},
+				{1, 1, 1, 1, 1, 1},
+			},
+			Enemies: []game.Enemy{enemy},
+		}
+		Expect(p.Validate()).To(BeNil())
+		path, Err := p.Solve()
+		Expect(Err).To(BeNil())
+		Expect(path.Length).To(Equal(4))
+		Expect(path.Waits).To(Equal(1))
+		Expect(path.Damage).To(Equal(0))
+		Expect(path.EnemyHits).To(BeEmpty())
+		Expect(path.Enemies).To(Equal([][]game.JI{
+			{{J: 3, I: 2}}, {{J: 3, I: 1}}, {{J: 3, I: 2}}, {{J: 3, I: 1}}, {{J: 3, I: 2}},
+		}))
+
+		By("checking that a weak enemy hit is absorbed", func() {
+			p.Enemies[0].Damage = 1
+			path, Err = p.Solve()
+			Expect(Err).To(BeNil())
+			Expect(path.Length).To(Equal(3))
+			Expect(path.Damage).To(Equal(1))
+			Expect(path.RemainingHP).To(Equal(3))
+			Expect(path.EnemyHits).To(Equal([]game.EnemyHit{{Enemy: 0, Cell: game.JI{J: 3, I: 1}, Tick: 1, Damage: 1}}))
+		})
+
+		By("checking that the player can not pass an enemy moving in the opposite way", func() {
+			p.Enemies[0] = game.Enemy{Route: []game.JI{{J: 4, I: 1}, {J: 3, I: 1}}, Rate: 1, Damage: 4}
+			_, Err = p.Solve()
+			Expect(Err).NotTo(BeNil())
+			Expect(Err.Code).To(Equal(service.ErrNoSurvivablePath))
+		})
+
+		By("checking that enemies are restored from the storage format", func() {
+			p.X, p.Y = 6, 4
+			Expect(game.FromStorage(p.ToStorage())).To(Equal(p))
+		})
+	})
+
+	It("checks that traps are checked on entering and enemies on every tick of wading through slow terrain", func() {
+		mud := game.JI{J: 2, I: 1}
+		p := game.Position{
+			Maze: [][]byte{
+				{1, 1, 1, 1, 1},
+				{1, 4, game.CellMud, 0, 0},
+				{1, 1, 0, 1, 1},
+				{1, 1, 1, 1, 1},
+			},
+			// active on ticks 0 and 1 of each 4, the player waits to enter the mud on inactive tick 2,
+			// the trap is checked on entering only, so staying in the mud on active tick 4 is harmless
+			PeriodicTraps: []game.PeriodicTrap{{Cell: mud, Period: 4, Active: 2, Damage: 4}},
+		}
+		Expect(p.Validate()).To(BeNil())
+		path, Err := p.Solve()
+		Expect(Err).To(BeNil())
+		Expect(path.Cost).To(Equal(6))
+		Expect(path.Waits).To(Equal(1))
+		Expect(path.Damage).To(Equal(0))
+		Expect(path.Traps).To(Equal([]game.TrapPass{{Cell: mud, Tick: 2, Damage: 0}}))
+
+		By("checking that an enemy passing the mud while the player is in it hits the player", func() {
+			p.PeriodicTraps = nil
+			// at the mud on odd ticks
+			p.Enemies = []game.Enemy{{Route: []game.JI{{J: 2, I: 2}, mud}, Rate: 1, Damage: 1}}
+			Expect(p.Validate()).To(BeNil())
+			path, Err = p.Solve()
+			Expect(Err).To(BeNil())
+			Expect(path.Cost).To(Equal(5))
+			Expect(path.Damage).To(Equal(2))
+			Expect(path.EnemyHits).To(Equal([]game.EnemyHit{
+				{Enemy: 0, Cell: mud, Tick: 1, Damage: 1},
+				{Enemy: 0, Cell: mud, Tick: 3, Damage: 1},
+			}))
+
+			// the mud can not be waded through without meeting the enemy on an odd tick
+			p.Enemies[0].Damage = 4
+			_, Err = p.Solve()
+			Expect(Err).NotTo(BeNil())
+			Expect(Err.Code).To(Equal(service.ErrNoSurvivablePath))
+		})
+	})
+
+	It("checks that the nearest exit is chosen according to the exit mode", func() {
+		p := game.Position{Maze: [][]byte{
+			{1, 1, 1, 1, 1, 1, 1},
+			{0, 0, 0, 4, 0, game.CellExit, 1},
+			{1, 1, 1, 1, 1, 1, 1},
+		}}
+		Expect(p.Exits()).To(Equal([]game.JI{{J: 0, I: 1}, {J: 5, I: 1}}))
+		path, Err := p.Solve()
+		Expect(Err).To(BeNil())
+		Expect(path.Length).To(Equal(2))
+
+		p.Rules = game.DefaultRules()
+		p.Rules.ExitMode = game.ExitModeBorder
+		Expect(p.Exits()).To(Equal([]game.JI{{J: 0, I: 1}}))
+		path, Err = p.Solve()
+		Expect(Err).To(BeNil())
+		Expect(path.Length).To(Equal(3))
+
+		p.Rules.ExitMode = game.ExitModeTile
+		Expect(p.Exits()).To(Equal([]game.JI{{J: 5, I: 1}}))
+		rules, err := game.ValidatorsByNames([]string{game.RuleEnclosedBorder})
+		Expect(err).NotTo(HaveOccurred())
+		errs := p.ValidateAll(rules...)
+		Expect(errs).To(HaveLen(1))
+		Expect(errs[0].Code).To(Equal(service.ErrValidationBorderIsNotEnclosed))
+		Expect(errs[0].Cell).To(Equal(&game.JI{J: 0, I: 1}))
+	})
+
+	It("checks that the movement topology of the position is honoured", func() {
+		type tcs struct {
+			maze     [][]byte
+			topology string
+			exitMode string
+			length   int // zero means no path
+		}
+		diagonal := [][]byte{{1, 1, 1, 0}, {1, 1, 0, 1}, {1, 4, 1, 1}, {1, 1, 1, 1}}
+		hex := [][]byte{{1, 1, 0, 1}, {1, 4, 1, 1}, {1, 1, 1, 1}}
+		torus := [][]byte{{1, 1, 1, 1, 1}, {0, 4, 1, game.CellExit, 0}, {1, 1, 1, 1, 1}}
+		for i, tc := range []tcs{
+			{maze: diagonal, topology: ""},
+			{maze: diagonal, topology: game.Topology4Way},
+			{maze: diagonal, topology: game.Topology8Way, length: 2},
+			{maze: diagonal, topology: game.Topology8WayNoCorners},
+			{maze: hex, topology: game.Topology4Way},
+			{maze: hex, topology: game.TopologyHex, length: 1},
+			{maze: torus, topology: game.Topology4Way, exitMode: game.ExitModeTile},
+			{maze: torus, topology: game.TopologyTorus, exitMode: game.ExitModeTile, length: 3},
+			// open cells on the edges of the torus are not exits
+			{maze: torus, topology: game.TopologyTorus, length: 3},
+			{maze: torus, topology: game.TopologyTorus, exitMode: game.ExitModeBoth, length: 3},
+		} {
+			p := game.Position{Maze: tc.maze, Topology: tc.topology, Rules: game.DefaultRules()}
+			if tc.exitMode != "" {
+				p.Rules.ExitMode = tc.exitMode
+			}
+			Expect(p.Validate()).To(BeNil(), "case %d", i)
+			path, Err := p.Solve()
+			if tc.length == 0 {
+				Expect(Err).NotTo(BeNil(), "case %d", i)
+				Expect(Err.Code).To(Equal(service.ErrNoSurvivablePath), "case %d", i)
+				continue
+			}
+			Expect(Err).To(BeNil(), "case %d", i)
+			Expect(path.Length).To(Equal(tc.length), "case %d", i)
+		}
+
+		rules := game.DefaultRules()
+		rules.ExitMode = game.ExitModeBorder
+		_, Err := game.Position{Maze: torus, Topology: game.TopologyTorus, Rules: rules}.Solve()
+		Expect(Err).NotTo(BeNil())
+		Expect(Err.Code).To(Equal(service.ErrLevelHasNoExit))
+
+		p := game.Position{X: 4, Y: 3, Maze: hex, Topology: game.TopologyHex}
+		Expect(game.FromStorage(p.ToStorage())).To(Equal(p))
+	})
+
+	It("checks that slow terrain is avoided by a cheaper detour", func() {
+		p := game.Position{Maze: [][]byte{
+			{1, 1, 1, 1, 1, 1},
+			{1, 4, game.CellMud, game.CellMud, 0, 1},
+			{1, 0, 1, 1, 0, 1},
+			{1, 0, game.CellWater, 0, 0, 1},
+			{1, 1, 1, 1, 0, 1},
+		}}
+		path, Err := p.Solve()
+		Expect(Err).To(BeNil())
+		Expect(path.Length).To(Equal(6))
+		Expect(path.Cost).To(Equal(7))
+		Expect(path.Cells[1]).To(Equal(game.JI{J: 1, I: 2}))
+
+		p.Maze[2][1] = game.CellWall
+		path, Err = p.Solve()
+		Expect(Err).To(BeNil())
+		Expect(path.Length).To(Equal(6))
+		Expect(path.Cost).To(Equal(10))
+		Expect(path.Cells[1]).To(Equal(game.JI{J: 2, I: 1}))
+	})
+
+	It("checks that A* search finds paths of the same cost as the breadth-first one", func() {
+		rnd := rand.New(rand.NewSource(1))
+		tiles := []byte{
+			game.CellOpen, game.CellOpen, game.CellOpen, game.CellWall, game.CellWall, game.CellPit,
+			game.CellArrow, game.CellMud, game.CellWater, game.CellRandomTrap,
+		}
+		topologies := []string{
+			game.Topology4Way, game.Topology8Way, game.Topology8WayNoCorners, game.TopologyHex, game.TopologyTorus,
+		}
+		rules := game.DefaultRules()
+		rules.ExitMode = game.ExitModeTile
+		solved := 0
+		for i := 0; i < 100; i++ {
+			p := game.Position{
+				Maze:     make([][]byte, 5+rnd.Intn(10)),
+				Topology: topologies[i%len(topologies)],
+				Rules:    rules,
+			}
+			width := 5 + rnd.Intn(10)
+			for k := range p.Maze {
+				p.Maze[k] = make([]byte, width)
+				for j := range p.Maze[k] {
+					p.Maze[k][j] = tiles[rnd.Intn(len(tiles))]
+				}
+			}
+			p.Maze[rnd.Intn(len(p.Maze))][rnd.Intn(width)] = game.CellPlayer
+			p.Maze[rnd.Intn(len(p.Maze))][rnd.Intn(width)] = game.CellExit
+
+			expected, expectedErr := p.Solve()
+			path, Err := p.SolveAStar()
+			Expect(Err).To(Equal(expectedErr), "case %d", i)
+			if expectedErr == nil {
+				Expect(path.Cost).To(Equal(expected.Cost), "case %d", i)
+				Expect(path.RemainingHP).To(BeNumerically(">", 0), "case %d", i)
+				solved++
+			}
+
+			expected, expectedErr = p.SolveWithChance(0.5)
+			path, Err = p.SolveAStarWithChance(0.5)
+			Expect(Err).To(Equal(expectedErr), "case %d", i)
+			if expectedErr == nil {
+				Expect(path.Cost).To(Equal(expected.Cost), "case %d", i)
+				Expect(path.SurvivalChance).To(BeNumerically(">=", 0.5), "case %d", i)
+			}
+		}
+		Expect(solved).To(BeNumerically(">", 50))
+	})
+
+	It("checks that A* search honours cheap teleportations", func() {
+		p := game.Position{
+			Maze: [][]byte{
+				{1, 1, 1, 1, 1, 1, 1, 1},
+				{1, 4, game.CellTeleporter, 0, 0, 0, game.CellTeleporter, game.CellExit},
+				{1, 1, 1, 1, 1, 1, 1, 1},
+			},
+			Teleporters: []game.Teleporter{{A: game.JI{J: 2, I: 1}, B: game.JI{J: 6, I: 1}}},
+		}
+		path, Err := p.SolveAStar()
+		Expect(Err).To(BeNil())
+		Expect(path.Cost).To(Equal(3))
+	})
+
+	It("checks that absence of a survivable path is reported", func() {
+		p := game.Position{Maze: [][]byte{
+			{1, 1, 1, 1, 1},
+			{1, 4, 3, 3, 0},
+			{1, 1, 1, 1, 1},
+		}}
+		path, _, err := solve(p)
+		Expect(err).To(Equal(game.ErrNoSurvivablePath))
+		Expect(path).To(BeNil())
+	})
+})
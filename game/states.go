@@ -0,0 +1,61 @@
+package game
+
+import (
+	"fmt"
+	"math"
+
+	"github.com/mtfelian/gjg-test-task/service"
+)
+
+// stateSpace returns the upper bound of the number of search states of the structurally valid position p
+// without potions: each passable cell may be reached on each tick of the cycle of periodic traps and enemies
+// and with each HP. Each potion doubles the bound, as it may be used or not. The number of potions is returned too.
+func stateSpace(p Position) (bound float64, potions int) {
+	rules := p.rules()
+	cells := 0
+	for _, row := range p.Maze {
+		for _, cell := range row {
+			if rules.Palette.Passable(cell) {
+				cells++
+			}
+			if rules.Palette[cell].Heal > 0 {
+				potions++
+			}
+		}
+	}
+	cycle := cycleOf(append(trapPeriods(p.PeriodicTraps), enemyPeriods(p.Enemies)...))
+	return float64(cells) * float64(cycle) * float64(rules.StartingHP), potions
+}
+
+// validateStateSpace checks that the number of search states of the structurally valid position p
+// with valid periodic traps and enemies does not exceed the max number of states of the rules.
+// The number of potions is limited by the states left after cells, ticks and HP.
+func validateStateSpace(p Position) Errors {
+	bound, potions := stateSpace(p)
+	maxStates := p.rules().maxStates()
+	if bound > float64(maxStates) {
+		return Errors{{
+			Code:    service.ErrValidationStateSpaceIsTooLarge,
+			Message: fmt.Sprintf("Position may have up to %.0f search states, max is %d", bound, maxStates),
+			Params:  []interface{}{bound, maxStates},
+		}}
+	}
+	if maxPotions := maxPotionsOf(bound, maxStates); potions > maxPotions {
+		return Errors{{
+			Code:    service.ErrValidationTooManyPotions,
+			Message: fmt.Sprintf("Position contains %d potions, max is %d", potions, maxPotions),
+			Params:  []interface{}{potions, maxPotions},
+		}}
+	}
+	return nil
+}
+
+// maxPotionsOf returns the max number of potions keeping the given bound of the number of search states
+// without potions within maxStates, but not more than MaxPotions
+func maxPotionsOf(bound float64, maxStates int) int {
+	res := int(math.Floor(math.Log2(float64(maxStates) / bound)))
+	if bound == 0 || res > MaxPotions {
+		return MaxPotions
+	}
+	return res
+}
@@ -0,0 +1,100 @@
+package game
+
+import (
+	"fmt"
+
+	"github.com/mtfelian/gjg-test-task/service"
+	"github.com/mtfelian/gjg-test-task/storage/model"
+)
+
+// Teleporter is a pair of linked teleporter cells. Entering one of them moves the player to another.
+type Teleporter struct {
+	A    JI  `json:"a"`
+	B    JI  `json:"b"`
+	Cost int `json:"cost"` // move cost of the teleportation, zero means the default one
+}
+
+// cost of the teleportation according to the rules
+func (t Teleporter) cost(rules *Rules) int {
+	if t.Cost > 0 {
+		return t.Cost
+	}
+	return rules.TeleportCost
+}
+
+// teleportersToStorage converts teleporters to the storage layer format
+func teleportersToStorage(teleporters []Teleporter) []model.Teleporter {
+	res := make([]model.Teleporter, len(teleporters))
+	for k, t := range teleporters {
+		res[k] = model.Teleporter{AX: t.A.J, AY: t.A.I, BX: t.B.J, BY: t.B.I, Cost: t.Cost}
+	}
+	return res
+}
+
+// teleportersFromStorage converts teleporters from the storage layer format
+func teleportersFromStorage(teleporters []model.Teleporter) []Teleporter {
+	if len(teleporters) == 0 {
+		return nil
+	}
+	res := make([]Teleporter, len(teleporters))
+	for k, t := range teleporters {
+		res[k] = Teleporter{A: JI{t.AX, t.AY}, B: JI{t.BX, t.BY}, Cost: t.Cost}
+	}
+	return res
+}
+
+// validateTeleporters checks that each teleporter tile of the structurally valid position p
+// belongs to exactly one pair of non-adjacent teleporters
+func validateTeleporters(p Position) (errs Errors) {
+	palette := p.rules().Palette
+	isTeleporter := func(c JI) bool {
+		return c.I >= 0 && c.I < len(p.Maze) && c.J >= 0 && c.J < len(p.Maze[c.I]) &&
+			palette[p.Maze[c.I][c.J]].Teleporter
+	}
+	ambiguous := func(k int, c JI, reason string) Error {
+		return Error{
+			Code:    service.ErrValidationTeleporterIsAmbiguous,
+			Message: fmt.Sprintf("Teleporter pair %d cell (%d,%d) %s", k, c.I, c.J, reason),
+			Params:  []interface{}{k, c.I, c.J},
+			Cell:    &JI{c.J, c.I},
+		}
+	}
+
+	paired := map[JI]bool{}
+	for k, t := range p.Teleporters {
+		for _, c := range []JI{t.A, t.B} {
+			switch {
+			case !isTeleporter(c):
+				errs = append(errs, ambiguous(k, c, "is not a teleporter"))
+			case paired[c]:
+				errs = append(errs, ambiguous(k, c, "belongs to more than one pair"))
+			}
+			paired[c] = true
+		}
+		if t.A == t.B || p.adjacent(t.A, t.B) {
+			errs = append(errs, ambiguous(k, t.A, "is the same or adjacent to its partner"))
+		}
+		if t.Cost < 0 {
+			errs = append(errs, Error{
+				Code:    service.ErrValidationTeleporterIsInvalid,
+				Message: fmt.Sprintf("Teleporter pair %d has negative cost %d", k, t.Cost),
+				Params:  []interface{}{k, t.Cost},
+				Cell:    &JI{t.A.J, t.A.I},
+			})
+		}
+	}
+
+	for i, row := range p.Maze {
+		for j, cell := range row {
+			if palette[cell].Teleporter && !paired[JI{j, i}] {
+				errs = append(errs, Error{
+					Code:    service.ErrValidationTeleporterIsUnpaired,
+					Message: fmt.Sprintf("Teleporter (%d,%d) has no pair", i, j),
+					Params:  []interface{}{i, j},
+					Cell:    &JI{j, i},
+				})
+			}
+		}
+	}
+	return
+}
@@ -0,0 +1,90 @@
+package game
+
+import (
+	"fmt"
+
+	"github.com/mtfelian/gjg-test-task/service"
+)
+
+// movement topologies
+const (
+	Topology4Way          = "4way"            // moves to side-adjacent cells of the square grid
+	Topology8Way          = "8way"            // moves to side- and corner-adjacent cells of the square grid
+	Topology8WayNoCorners = "8way_no_corners" // as 8way, but diagonal moves can not cut corners of walls
+	TopologyHex           = "hex"             // hex grid with odd rows shifted right by a half of the cell
+	TopologyTorus         = "torus"           // as 4way, but opposite borders are adjacent, so only tiles are exits
+)
+
+// offsets of moves
+var (
+	orthogonalMoves = []JI{{J: -1, I: 0}, {J: 1, I: 0}, {J: 0, I: -1}, {J: 0, I: 1}}
+	diagonalMoves   = []JI{{J: -1, I: -1}, {J: 1, I: -1}, {J: -1, I: 1}, {J: 1, I: 1}}
+	// hexMoves for even and odd rows
+	hexMoves = [2][]JI{
+		{{J: -1, I: 0}, {J: 1, I: 0}, {J: -1, I: -1}, {J: 0, I: -1}, {J: -1, I: 1}, {J: 0, I: 1}},
+		{{J: -1, I: 0}, {J: 1, I: 0}, {J: 0, I: -1}, {J: 1, I: -1}, {J: 0, I: 1}, {J: 1, I: 1}},
+	}
+)
+
+// IsValidTopology returns true if topology is a known movement topology
+func IsValidTopology(topology string) bool {
+	switch topology {
+	case Topology4Way, Topology8Way, Topology8WayNoCorners, TopologyHex, TopologyTorus:
+		return true
+	}
+	return false
+}
+
+// topology returns the movement topology of the position, 4way if it is not set
+func (p Position) topology() string {
+	if p.Topology == "" {
+		return Topology4Way
+	}
+	return p.Topology
+}
+
+// moves returns offsets of moves from a cell of the row i
+func (p Position) moves(i int) []JI {
+	switch p.topology() {
+	case Topology8Way, Topology8WayNoCorners:
+		return append(append([]JI(nil), orthogonalMoves...), diagonalMoves...)
+	case TopologyHex:
+		return hexMoves[i%2]
+	}
+	return orthogonalMoves
+}
+
+// neighbour returns the cell reached from c by the move d, ok is false if it is out of the maze
+func (p Position) neighbour(c, d JI) (n JI, ok bool) {
+	n = JI{c.J + d.J, c.I + d.I}
+	if p.topology() == TopologyTorus && len(p.Maze) > 0 {
+		height, width := len(p.Maze), len(p.Maze[0])
+		n = JI{(n.J%width + width) % width, (n.I%height + height) % height}
+	}
+	return n, n.I >= 0 && n.I < len(p.Maze) && n.J >= 0 && n.J < len(p.Maze[n.I])
+}
+
+// adjacent returns true if the cell b can be reached from the different cell a by one move
+func (p Position) adjacent(a, b JI) bool {
+	if a == b || a.I < 0 || a.I >= len(p.Maze) {
+		return false
+	}
+	for _, d := range p.moves(a.I) {
+		if n, ok := p.neighbour(a, d); ok && n == b {
+			return true
+		}
+	}
+	return false
+}
+
+// validateTopology checks that the movement topology of the position is known
+func validateTopology(p Position) Errors {
+	if p.Topology == "" || IsValidTopology(p.Topology) {
+		return nil
+	}
+	return Errors{{
+		Code:    service.ErrValidationTopologyIsUnknown,
+		Message: fmt.Sprintf("Unknown movement topology %q", p.Topology),
+		Params:  []interface{}{p.Topology},
+	}}
+}
@@ -0,0 +1,85 @@
+package game
+
+// types of search events
+const (
+	TraceEnqueue  = "enqueue"   // a state is queued to be expanded
+	TraceVisit    = "visit"     // a state is expanded
+	TraceHPChange = "hp_change" // HP of the player changes on entering the cell
+	TracePrune    = "prune"     // a state is discarded
+)
+
+// reasons of discarding states
+const (
+	PruneDead     = "dead"     // the player dies entering the cell
+	PruneCostlier = "costlier" // the state is already reached with not greater move cost
+)
+
+// SearchTracer receives events of the path search. Cost is the move cost of reaching the state.
+type SearchTracer interface {
+	Enqueue(cell JI, hp, cost int)
+	Visit(cell JI, hp, cost int)
+	HPChange(cell JI, from, to int)
+	Prune(cell JI, hp int, reason string)
+}
+
+// NopTracer ignores all events, it is used if no tracer is set
+type NopTracer struct{}
+
+func (NopTracer) Enqueue(JI, int, int)  {}
+func (NopTracer) Visit(JI, int, int)    {}
+func (NopTracer) HPChange(JI, int, int) {}
+func (NopTracer) Prune(JI, int, string) {}
+
+// tracerOr returns t, the no-op tracer if t is nil
+func tracerOr(t SearchTracer) SearchTracer {
+	if t == nil {
+		return NopTracer{}
+	}
+	return t
+}
+
+// TraceEvent is an event of the path search
+type TraceEvent struct {
+	Type   string `json:"type"`
+	Cell   JI     `json:"cell"`
+	HP     int    `json:"hp"`                // HP of the state, the new one for HP changes
+	FromHP int    `json:"from_hp,omitempty"` // HP before the change
+	Cost   int    `json:"cost"`
+	Reason string `json:"reason,omitempty"` // reason of discarding the state
+}
+
+// SearchTrace records events of the path search in order of their occurrence, implements SearchTracer
+type SearchTrace struct {
+	Events    []TraceEvent
+	MaxEvents int  // max number of events recorded, zero means no limit
+	Truncated bool // true if some events are not recorded because of MaxEvents
+}
+
+// record the event e if the limit of events is not reached
+func (t *SearchTrace) record(e TraceEvent) {
+	if t.MaxEvents > 0 && len(t.Events) >= t.MaxEvents {
+		t.Truncated = true
+		return
+	}
+	t.Events = append(t.Events, e)
+}
+
+// Enqueue records the event of queueing the state
+func (t *SearchTrace) Enqueue(cell JI, hp, cost int) {
+	t.record(TraceEvent{Type: TraceEnqueue, Cell: cell, HP: hp, Cost: cost})
+}
+
+// Visit records the event of expanding the state
+func (t *SearchTrace) Visit(cell JI, hp, cost int) {
+	t.record(TraceEvent{Type: TraceVisit, Cell: cell, HP: hp, Cost: cost})
+}
+
+// HPChange records the event of changing HP on entering the cell
+func (t *SearchTrace) HPChange(cell JI, from, to int) {
+	t.record(TraceEvent{Type: TraceHPChange, Cell: cell, HP: to, FromHP: from})
+}
+
+// Prune records the event of discarding the state
+func (t *SearchTrace) Prune(cell JI, hp int, reason string) {
+	t.record(TraceEvent{Type: TracePrune, Cell: cell, HP: hp, Reason: reason})
+}
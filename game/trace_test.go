@@ -0,0 +1,73 @@
+package game_test
+
+import (
+	"github.com/mtfelian/gjg-test-task/game"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("search tracing", func() {
+	maze := func() [][]byte {
+		return [][]byte{
+			{1, 1, 1, 1, 1},
+			{1, 4, 2, 0, 1},
+			{1, 0, 1, 0, 1},
+			{1, 0, 0, 0, 1},
+			{1, 1, 1, 0, 1},
+		}
+	}
+
+	It("checks events of the minimum survivable path search", func() {
+		trace := &game.SearchTrace{}
+		path, Err := game.Position{Maze: maze()}.SolveTraced(game.SolverBFS, trace)
+		Expect(Err).To(BeNil())
+		Expect(path.Cost).To(Equal(5))
+		Expect(trace.Events[0]).To(Equal(game.TraceEvent{Type: game.TraceEnqueue, Cell: game.JI{J: 1, I: 1}, HP: 4}))
+		Expect(trace.Events[1]).To(Equal(game.TraceEvent{Type: game.TraceVisit, Cell: game.JI{J: 1, I: 1}, HP: 4}))
+		Expect(trace.Events[2]).To(Equal(game.TraceEvent{
+			Type: game.TraceHPChange, Cell: game.JI{J: 2, I: 1}, HP: 3, FromHP: 4,
+		}))
+		Expect(trace.Events[3]).To(Equal(game.TraceEvent{Type: game.TraceEnqueue, Cell: game.JI{J: 2, I: 1}, HP: 3, Cost: 1}))
+
+		last := trace.Events[len(trace.Events)-1]
+		Expect(last).To(Equal(game.TraceEvent{Type: game.TraceVisit, Cell: game.JI{J: 3, I: 4}, HP: 3, Cost: 5}))
+		for _, e := range trace.Events {
+			if e.Type == game.TraceVisit {
+				Expect(e.Cost).To(BeNumerically("<=", last.Cost)) // states are expanded in order of their costs
+			}
+		}
+	})
+
+	It("checks that A* search expands less states and deadly moves are pruned", func() {
+		p := game.Position{Maze: maze()}
+		bfs, astar := &game.SearchTrace{}, &game.SearchTrace{}
+		_, Err := p.SolveTraced(game.SolverBFS, bfs)
+		Expect(Err).To(BeNil())
+		_, Err = p.SolveTraced(game.SolverAStar, astar)
+		Expect(Err).To(BeNil())
+		Expect(len(astar.Events)).To(BeNumerically("<", len(bfs.Events)))
+
+		p.Rules = game.DefaultRules()
+		p.Rules.StartingHP = 1
+		trace := &game.SearchTrace{}
+		_, Err = p.SolveTraced(game.SolverBFS, trace)
+		Expect(Err).To(BeNil())
+		Expect(trace.Events).To(ContainElement(game.TraceEvent{
+			Type: game.TracePrune, Cell: game.JI{J: 2, I: 1}, HP: 0, Reason: game.PruneDead,
+		}))
+		Expect(trace.Events).To(ContainElement(game.TraceEvent{
+			Type: game.TracePrune, Cell: game.JI{J: 1, I: 1}, HP: 1, Reason: game.PruneCostlier,
+		}))
+	})
+
+	It("checks that events are not recorded over the limit", func() {
+		full, limited := &game.SearchTrace{}, &game.SearchTrace{MaxEvents: 5}
+		_, Err := game.Position{Maze: maze()}.SolveTraced(game.SolverBFS, full)
+		Expect(Err).To(BeNil())
+		Expect(full.Truncated).To(BeFalse())
+		_, Err = game.Position{Maze: maze()}.SolveTraced(game.SolverBFS, limited)
+		Expect(Err).To(BeNil())
+		Expect(limited.Events).To(Equal(full.Events[:5]))
+		Expect(limited.Truncated).To(BeTrue())
+	})
+})
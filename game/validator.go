@@ -0,0 +1,137 @@
+package game
+
+import (
+	"fmt"
+
+	"github.com/mtfelian/gjg-test-task/service"
+)
+
+// Validator is a level validation rule
+type Validator interface {
+	// Name of the rule to refer it in the configuration
+	Name() string
+	// Validate the position. It is invoked only for positions passed structural validation.
+	Validate(p Position) Errors
+}
+
+// built-in validation rule names
+const (
+	RuleSingleStart    = "single_start"
+	RuleEnclosedBorder = "enclosed_border"
+	RuleHasExit        = "has_exit"
+	RuleSurvivablePath = "survivable_path"
+)
+
+// validatorFunc adapts a function to the Validator interface
+type validatorFunc struct {
+	name     string
+	validate func(p Position) Errors
+}
+
+// Name makes validatorFunc to implement Validator
+func (v validatorFunc) Name() string { return v.name }
+
+// Validate makes validatorFunc to implement Validator
+func (v validatorFunc) Validate(p Position) Errors { return v.validate(p) }
+
+// validators registry
+var validators = map[string]Validator{}
+
+// RegisterValidator makes the rule v available by its name, it replaces the rule registered with the same name
+func RegisterValidator(v Validator) { validators[v.Name()] = v }
+
+// ValidatorsByNames returns registered rules with the given names
+func ValidatorsByNames(names []string) ([]Validator, error) {
+	res := make([]Validator, len(names))
+	for i, name := range names {
+		v, ok := validators[name]
+		if !ok {
+			return nil, fmt.Errorf("unknown validation rule: %s", name)
+		}
+		res[i] = v
+	}
+	return res, nil
+}
+
+func init() {
+	RegisterValidator(validatorFunc{name: RuleSingleStart, validate: validateSingleStart})
+	RegisterValidator(validatorFunc{name: RuleEnclosedBorder, validate: validateEnclosedBorder})
+	RegisterValidator(validatorFunc{name: RuleHasExit, validate: validateHasExit})
+	RegisterValidator(validatorFunc{name: RuleSurvivablePath, validate: validateSurvivablePath})
+}
+
+// validateSingleStart checks that there is exactly one player starting position
+func validateSingleStart(p Position) Errors {
+	var starts []JI
+	for i, row := range p.Maze {
+		for j, cell := range row {
+			if cell == CellPlayer {
+				starts = append(starts, JI{j, i})
+			}
+		}
+	}
+	switch len(starts) {
+	case 0:
+		return Errors{{
+			Code:    service.ErrValidationStartIsNotSingle,
+			Message: "Position has no player starting position",
+			Params:  []interface{}{0},
+		}}
+	case 1:
+		return nil
+	}
+	errs := make(Errors, len(starts))
+	for k, start := range starts {
+		start := start
+		errs[k] = Error{
+			Code:    service.ErrValidationStartIsNotSingle,
+			Message: fmt.Sprintf("Position has %d player starting positions, one is at (%d,%d)", len(starts), start.I, start.J),
+			Params:  []interface{}{len(starts), start.I, start.J},
+			Cell:    &start,
+		}
+	}
+	return errs
+}
+
+// validateEnclosedBorder checks that the border consists of walls and exits only, the torus has no border
+func validateEnclosedBorder(p Position) (errs Errors) {
+	if p.topology() == TopologyTorus {
+		return nil
+	}
+	for i, row := range p.Maze {
+		for j, cell := range row {
+			if i > 0 && i < len(p.Maze)-1 && j > 0 && j < len(row)-1 {
+				continue
+			}
+			if cell != CellWall && !p.isExit(i, j) {
+				errs = append(errs, Error{
+					Code:    service.ErrValidationBorderIsNotEnclosed,
+					Message: fmt.Sprintf("Border cell (%d,%d) contains value %d, it should be a wall or an exit", i, j, cell),
+					Params:  []interface{}{i, j, cell},
+					Cell:    &JI{j, i},
+				})
+			}
+		}
+	}
+	return
+}
+
+// validateHasExit checks that there is at least one exit
+func validateHasExit(p Position) Errors {
+	if len(p.Exits()) > 0 {
+		return nil
+	}
+	return Errors{{Code: service.ErrValidationNoExit, Message: "Position has no exits"}}
+}
+
+// validateSurvivablePath checks that the exit can be reached alive from the player starting position
+func validateSurvivablePath(p Position) Errors {
+	if _, Err := p.SolveAStar(); Err != nil {
+		return Errors{{
+			Code:    service.ErrValidationNoSurvivablePath,
+			Message: "Position has no survivable path: " + Err.Message,
+			Params:  Err.Params,
+		}}
+	}
+	return nil
+}
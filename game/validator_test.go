@@ -0,0 +1,244 @@
+package game_test
+
+import (
+	"github.com/mtfelian/gjg-test-task/game"
+	"github.com/mtfelian/gjg-test-task/service"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("validation rules", func() {
+	allRules := []string{game.RuleSingleStart, game.RuleEnclosedBorder, game.RuleHasExit, game.RuleSurvivablePath}
+
+	It("checks built-in rules", func() {
+		rules, err := game.ValidatorsByNames(allRules)
+		Expect(err).NotTo(HaveOccurred())
+
+		type tcs struct {
+			maze          [][]byte
+			expectedCodes []int
+		}
+		for i, tc := range []tcs{
+			{ // normal case
+				maze: [][]byte{
+					{1, 1, 0, 1},
+					{1, 4, 2, 1},
+					{1, 1, 1, 1},
+				},
+			},
+			{
+				maze: [][]byte{
+					{1, 1, 0, 1},
+					{1, 0, 2, 1},
+					{1, 1, 1, 1},
+				},
+				expectedCodes: []int{service.ErrValidationStartIsNotSingle, service.ErrValidationNoSurvivablePath},
+			},
+			{
+				maze: [][]byte{
+					{1, 1, 0, 1},
+					{1, 4, 4, 1},
+					{1, 1, 1, 1},
+				},
+				expectedCodes: []int{service.ErrValidationStartIsNotSingle, service.ErrValidationStartIsNotSingle},
+			},
+			{
+				maze: [][]byte{
+					{1, 1, 0, 1},
+					{3, 4, 2, 1},
+					{1, 1, 2, 1},
+				},
+				expectedCodes: []int{service.ErrValidationBorderIsNotEnclosed, service.ErrValidationBorderIsNotEnclosed},
+			},
+			{
+				maze: [][]byte{
+					{1, 1, 1, 1},
+					{1, 4, 0, 1},
+					{1, 1, 1, 1},
+				},
+				expectedCodes: []int{service.ErrValidationNoExit, service.ErrValidationNoSurvivablePath},
+			},
+			{
+				maze: [][]byte{
+					{1, 1, 0, 1},
+					{1, 1, 3, 1},
+					{1, 4, 3, 1},
+					{1, 1, 1, 1},
+				},
+				expectedCodes: []int{service.ErrValidationNoSurvivablePath},
+			},
+		} {
+			errs := game.Position{Maze: tc.maze}.ValidateAll(rules...)
+			codes := make([]int, len(errs))
+			for k, e := range errs {
+				codes[k] = e.Code
+			}
+			if tc.expectedCodes == nil {
+				Expect(codes).To(BeEmpty(), "case %d", i)
+				continue
+			}
+			Expect(codes).To(Equal(tc.expectedCodes), "case %d", i)
+		}
+	})
+
+	It("checks that rules are not applied to structurally invalid positions", func() {
+		rules, err := game.ValidatorsByNames(allRules)
+		Expect(err).NotTo(HaveOccurred())
+		errs := game.Position{Maze: [][]byte{{1, 1, 0}, {1, 255}}}.ValidateAll(rules...)
+		Expect(errs).To(HaveLen(2))
+		Expect(errs[0].Code).To(Equal(service.ErrValidationFieldIsNotRectangular))
+		Expect(errs[1].Code).To(Equal(service.ErrValidationFieldHasInvalidData))
+	})
+
+	It("checks that the torus has no border to be enclosed or to exit through", func() {
+		rules, err := game.ValidatorsByNames(allRules)
+		Expect(err).NotTo(HaveOccurred())
+		p := game.Position{Maze: [][]byte{
+			{0, 1, 1, 0},
+			{0, 4, 2, game.CellExit},
+			{1, 1, 0, 1},
+		}, Topology: game.TopologyTorus, Rules: game.DefaultRules()}
+		Expect(p.ValidateAll(rules...)).To(BeEmpty())
+		Expect(p.Exits()).To(Equal([]game.JI{{J: 3, I: 1}}))
+
+		p.Rules.ExitMode = game.ExitModeBorder
+		errs := p.ValidateAll(rules...)
+		Expect(errs).To(HaveLen(2))
+		Expect(errs[0].Code).To(Equal(service.ErrValidationNoExit))
+		Expect(errs[1].Code).To(Equal(service.ErrValidationNoSurvivablePath))
+	})
+
+	It("checks that unpaired, ambiguous and invalid teleporters are rejected", func() {
+		maze := [][]byte{
+			{1, 1, 1, 1, 1, 1},
+			{1, 4, 12, 0, 12, 0},
+			{1, 12, 0, 0, 12, 1},
+			{1, 1, 1, 1, 1, 1},
+		}
+		t := func(aj, ai, bj, bi int) game.Teleporter {
+			return game.Teleporter{A: game.JI{J: aj, I: ai}, B: game.JI{J: bj, I: bi}}
+		}
+		type tcs struct {
+			teleporters   []game.Teleporter
+			expectedCodes []int
+		}
+		for i, tc := range []tcs{
+			{teleporters: []game.Teleporter{t(2, 1, 4, 1), t(1, 2, 4, 2)}},
+			{
+				teleporters:   []game.Teleporter{t(2, 1, 4, 1)},
+				expectedCodes: []int{service.ErrValidationTeleporterIsUnpaired, service.ErrValidationTeleporterIsUnpaired},
+			},
+			{
+				teleporters: []game.Teleporter{t(2, 1, 4, 1), t(2, 1, 4, 2), t(1, 2, 4, 2)},
+				expectedCodes: []int{
+					service.ErrValidationTeleporterIsAmbiguous,
+					service.ErrValidationTeleporterIsAmbiguous,
+				},
+			},
+			{
+				teleporters:   []game.Teleporter{t(2, 1, 1, 2), t(4, 1, 4, 2)},
+				expectedCodes: []int{service.ErrValidationTeleporterIsAmbiguous},
+			},
+			{
+				teleporters: []game.Teleporter{t(2, 1, 3, 1), t(1, 2, 4, 2)},
+				expectedCodes: []int{
+					service.ErrValidationTeleporterIsAmbiguous,
+					service.ErrValidationTeleporterIsAmbiguous,
+					service.ErrValidationTeleporterIsUnpaired,
+				},
+			},
+			{
+				teleporters:   []game.Teleporter{t(2, 1, 4, 1), t(1, 2, 4, 20)},
+				expectedCodes: []int{service.ErrValidationTeleporterIsAmbiguous, service.ErrValidationTeleporterIsUnpaired},
+			},
+			{
+				teleporters: []game.Teleporter{
+					t(2, 1, 4, 1), {A: game.JI{J: 1, I: 2}, B: game.JI{J: 4, I: 2}, Cost: -1},
+				},
+				expectedCodes: []int{service.ErrValidationTeleporterIsInvalid},
+			},
+		} {
+			errs := game.Position{Maze: maze, Teleporters: tc.teleporters}.ValidateAll()
+			var codes []int
+			for _, err := range errs {
+				codes = append(codes, err.Code)
+			}
+			Expect(codes).To(Equal(tc.expectedCodes), "case %d", i)
+		}
+	})
+
+	It("checks that invalid periodic traps are rejected", func() {
+		maze := [][]byte{
+			{1, 1, 1, 1, 1},
+			{1, 4, 3, 2, 0},
+			{1, 1, 1, 1, 1},
+		}
+		trap := func(j, period, active int) game.PeriodicTrap {
+			return game.PeriodicTrap{Cell: game.JI{J: j, I: 1}, Period: period, Active: active}
+		}
+		for i, traps := range [][]game.PeriodicTrap{
+			{trap(0, 2, 1)},
+			{trap(2, 2, 1), trap(2, 3, 1)},
+			{trap(2, 0, 0)},
+			{trap(2, 2, 3)},
+			{trap(2, 7, 1), trap(3, 11, 1), trap(4, 13, 1)},
+		} {
+			errs := game.Position{Maze: maze, PeriodicTraps: traps}.ValidateAll()
+			Expect(errs).To(HaveLen(1), "case %d", i)
+			Expect(errs[0].Code).To(Equal(service.ErrValidationPeriodicTrapIsInvalid), "case %d", i)
+		}
+		Expect(game.Position{Maze: maze, PeriodicTraps: []game.PeriodicTrap{trap(2, 2, 1)}}.ValidateAll()).To(BeEmpty())
+	})
+
+	It("checks that invalid enemies are rejected", func() {
+		maze := [][]byte{
+			{1, 1, 1, 1, 1},
+			{1, 4, 0, 0, 0},
+			{1, 1, 1, 1, 1},
+		}
+		route := func(js ...int) (res []game.JI) {
+			for _, j := range js {
+				res = append(res, game.JI{J: j, I: 1})
+			}
+			return
+		}
+		for i, enemies := range [][]game.Enemy{
+			{{Route: route(), Rate: 1}},
+			{{Route: route(2, 3), Rate: 0}},
+			{{Route: route(0, 1), Rate: 1}},
+			{{Route: route(2, 4), Rate: 1}},
+			{{Route: route(2, 3), Rate: 1, Damage: -1}},
+			{{Route: route(2, 3, 4, 3), Rate: 31}},
+		} {
+			errs := game.Position{Maze: maze, Enemies: enemies}.ValidateAll()
+			Expect(errs).NotTo(BeEmpty(), "case %d", i)
+			Expect(errs[0].Code).To(Equal(service.ErrValidationEnemyIsInvalid), "case %d", i)
+		}
+		enemies := []game.Enemy{{Route: route(2, 3, 4, 3), Rate: 2, Damage: 1}}
+		Expect(game.Position{Maze: maze, Enemies: enemies}.ValidateAll()).To(BeEmpty())
+	})
+
+	It("checks that the topology is known and used to check adjacency", func() {
+		maze := [][]byte{
+			{1, 1, 1, 1, 1},
+			{1, 4, 12, 0, 0},
+			{1, 0, 0, 12, 1},
+			{1, 1, 1, 1, 1},
+		}
+		teleporters := []game.Teleporter{{A: game.JI{J: 2, I: 1}, B: game.JI{J: 3, I: 2}}}
+		Expect(game.Position{Maze: maze, Teleporters: teleporters}.ValidateAll()).To(BeEmpty())
+
+		errs := game.Position{Maze: maze, Teleporters: teleporters, Topology: game.Topology8Way}.ValidateAll()
+		Expect(errs).To(HaveLen(1))
+		Expect(errs[0].Code).To(Equal(service.ErrValidationTeleporterIsAmbiguous))
+
+		errs = game.Position{Maze: maze, Teleporters: teleporters, Topology: "triangle"}.ValidateAll()
+		Expect(errs).To(HaveLen(1))
+		Expect(errs[0].Code).To(Equal(service.ErrValidationTopologyIsUnknown))
+	})
+
+	It("checks that unknown rule can not be selected", func() {
+		_, err := game.ValidatorsByNames([]string{game.RuleHasExit, "unknown"})
+		Expect(err).To(HaveOccurred())
+	})
+})
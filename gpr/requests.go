@@ -2,8 +2,36 @@ package gpr
 
 import (
 	"net/http"
+
+	"github.com/go-openapi/strfmt"
 )
 
 func (g *GPR) PerformSubmitLevelRequest(JSON []byte, expectedStatusCode int, target interface{}) {
 	g.PerformRequest("/submit", http.MethodPost, JSON, expectedStatusCode, target)
 }
+
+func (g *GPR) PerformGetLevelsRequest(query string, expectedStatusCode int, target interface{}) {
+	g.PerformRequest("/levels?"+query, http.MethodGet, nil, expectedStatusCode, target)
+}
+
+func (g *GPR) PerformGetLevelRequest(id strfmt.UUID, expectedStatusCode int, target interface{}) {
+	g.PerformRequest("/levels/"+id.String(), http.MethodGet, nil, expectedStatusCode, target)
+}
+
+func (g *GPR) PerformGetLevelSolutionRequest(id strfmt.UUID, query string, expectedStatusCode int,
+	target interface{}) {
+	g.PerformRequest("/levels/"+id.String()+"/solution?"+query, http.MethodGet, nil, expectedStatusCode, target)
+}
+
+func (g *GPR) PerformGetLevelSolutionTraceRequest(id strfmt.UUID, query string, expectedStatusCode int,
+	target interface{}) {
+	g.PerformRequest("/levels/"+id.String()+"/solution/trace?"+query, http.MethodGet, nil, expectedStatusCode, target)
+}
+
+func (g *GPR) PerformGetLevelRoutesRequest(id strfmt.UUID, expectedStatusCode int, target interface{}) {
+	g.PerformRequest("/levels/"+id.String()+"/routes", http.MethodGet, nil, expectedStatusCode, target)
+}
+
+func (g *GPR) PerformGetLevelHPRequest(id strfmt.UUID, expectedStatusCode int, target interface{}) {
+	g.PerformRequest("/levels/"+id.String()+"/hp", http.MethodGet, nil, expectedStatusCode, target)
+}
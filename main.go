@@ -7,6 +7,7 @@ import (
 	"github.com/labstack/echo/v4/middleware"
 	"github.com/mtfelian/gjg-test-task/api"
 	"github.com/mtfelian/gjg-test-task/config"
+	"github.com/mtfelian/gjg-test-task/game"
 	"github.com/mtfelian/gjg-test-task/service"
 	"github.com/sirupsen/logrus"
 )
@@ -19,6 +20,12 @@ func RegisterHTTPAPIHandlers(router *echo.Echo) {
 	//router.Use(middleware.BodyDump(func(c echo.Context, reqBody, resBody []byte) { fmt.Printf("@@: %s\n", resBody) }))
 
 	router.POST("/submit", api.SubmitLevel)
+	router.GET("/levels", api.GetLevels)
+	router.GET("/levels/:id", api.GetLevel)
+	router.GET("/levels/:id/solution", api.GetLevelSolution)
+	router.GET("/levels/:id/solution/trace", api.GetLevelSolutionTrace)
+	router.GET("/levels/:id/routes", api.GetLevelRoutes)
+	router.GET("/levels/:id/hp", api.GetLevelHP)
 }
 
 func main() {
@@ -33,6 +40,20 @@ func main() {
 	}
 
 	s := service.Get()
+	if _, err = game.ValidatorsByNames(s.Conf.GetStringSlice(config.ValidationRules)); err != nil {
+		s.Logger.Fatal(err)
+	}
+	if _, err = api.GameRules(s.Conf); err != nil {
+		s.Logger.Fatal(err)
+	}
+	if s.Conf.GetBool(config.Rescore) {
+		count, err := api.RescoreLevels()
+		if err != nil {
+			s.Logger.Fatalf("Failed to rescore levels: %v", err)
+		}
+		s.Logger.Infof("Finished. Rescored %d levels", count)
+		return
+	}
 	RegisterHTTPAPIHandlers(s.HTTPServer)
 	if err = s.HTTPServer.Start(fmt.Sprintf(":%d", s.Conf.GetInt(config.Port))); err != nil {
 		s.Logger.Fatalf("HTTP server error: %v", err)
@@ -1,6 +1,7 @@
 package main_test
 
 import (
+	"bytes"
 	"math/rand"
 	"net/http"
 	"net/http/httptest"
@@ -19,6 +20,7 @@ import (
 	"github.com/mtfelian/utils"
 	. "github.com/onsi/ginkgo"
 	. "github.com/onsi/gomega"
+	uuid "github.com/satori/go.uuid"
 	"github.com/sirupsen/logrus"
 	"github.com/spf13/viper"
 )
@@ -93,6 +95,224 @@ var _ = Describe("Testing manager API", func() {
 			}
 		})
 
+		It("checks that the length score is calculated on submit", func() {
+			type tcs struct {
+				maze     [][]byte
+				expected api.SubmitLevelResponse
+			}
+			for i, tc := range []tcs{
+				{
+					maze: [][]byte{
+						{1, 1, 1, 1, 0, 1, 1, 1},
+						{1, 0, 0, 0, 0, 0, 0, 1},
+						{1, 0, 1, 1, 1, 3, 1, 1},
+						{1, 0, 0, 0, 1, 0, 2, 1},
+						{1, 1, 1, 0, 1, 1, 0, 1},
+						{1, 0, 0, 0, 1, 0, 0, 1},
+						{1, 0, 1, 1, 1, 0, 1, 1},
+						{1, 0, 0, 4, 0, 0, 0, 1},
+						{1, 1, 1, 1, 1, 1, 1, 1},
+					},
+					expected: api.SubmitLevelResponse{LengthScore: 12, MoveCost: 12, Damage: 3, Solvable: true},
+				},
+				{
+					maze: [][]byte{
+						{1, 1, 1, 1, 1},
+						{1, 4, game.CellMud, game.CellWater, 0},
+						{1, 1, 1, 1, 1},
+					},
+					expected: api.SubmitLevelResponse{LengthScore: 3, MoveCost: 6, Solvable: true},
+				},
+				{
+					maze: [][]byte{
+						{1, 1, 1, 1, 1},
+						{1, 4, 3, 3, 0},
+						{1, 1, 1, 1, 1},
+					},
+					expected: api.SubmitLevelResponse{Solvable: false},
+				},
+			} {
+				var r api.SubmitLevelResponse
+				g.PerformSubmitLevelRequest(utils.MushMarshalJSON(api.SubmitLevelParams{Maze: tc.maze}),
+					http.StatusCreated, &r)
+				Expect(r.LevelID).NotTo(BeEmpty(), "case %d", i)
+				tc.expected.LevelID = r.LevelID
+				Expect(r).To(Equal(tc.expected), "case %d", i)
+			}
+		})
+
+		It("checks that a level can be submitted as a bare array of arrays", func() {
+			var r api.SubmitLevelResponse
+			g.PerformSubmitLevelRequest([]byte(` [[1,1,1,0,1],[1,4,2,0,1],[1,1,1,1,1]]`), http.StatusCreated, &r)
+			Expect(r.LevelID).NotTo(BeEmpty())
+			Expect(r.LengthScore).To(Equal(3))
+			Expect(r.Damage).To(Equal(1))
+		})
+
+		It("checks that creating level fails, invalid case: cell value does not fit into a byte", func() {
+			for i, body := range []string{
+				`[[1,0,1],[1,4,-1]]`,
+				`{"maze":[[1,0,1],[1,4,256]]}`,
+			} {
+				var r game.Error
+				g.PerformSubmitLevelRequest([]byte(body), http.StatusBadRequest, &r)
+				Expect(r.Code).To(Equal(service.ErrValidationCellIsOutOfRange), "case %d", i)
+				Expect(r.Params).To(HaveLen(4), "case %d", i)
+				Expect(r.Params[:2]).To(BeEquivalentTo([]interface{}{1., 2.}), "case %d", i)
+			}
+		})
+
+		It("checks that creating level fails, invalid case: all violations are reported", func() {
+			var r api.ValidationErrorResponse
+			g.PerformSubmitLevelRequest([]byte(`[[1,0,1],[1,4,-1,250],[251,0,1]]`), http.StatusBadRequest, &r)
+			Expect(r.Code).To(Equal(service.ErrValidationCellIsOutOfRange))
+			codes := make([]int, len(r.Errors))
+			for i, e := range r.Errors {
+				codes[i] = e.Code
+			}
+			Expect(codes).To(Equal([]int{
+				service.ErrValidationCellIsOutOfRange,
+				service.ErrValidationFieldIsNotRectangular,
+				service.ErrValidationFieldHasInvalidData,
+				service.ErrValidationFieldHasInvalidData,
+			}))
+			Expect(r.Errors[0].Cell).To(Equal(&game.JI{J: 2, I: 1}))
+			Expect(r.Errors[3].Cell).To(Equal(&game.JI{J: 0, I: 2}))
+		})
+
+		It("checks that creating level fails, invalid case: configured validation rules are violated", func() {
+			viper.Set(config.ValidationRules, []string{game.RuleSingleStart, game.RuleSurvivablePath})
+			defer viper.Set(config.ValidationRules, nil)
+
+			var r api.ValidationErrorResponse
+			g.PerformSubmitLevelRequest([]byte(`[[1,1,0,1],[1,4,4,1],[1,3,3,1],[1,1,1,1]]`), http.StatusBadRequest, &r)
+			Expect(r.Code).To(Equal(service.ErrValidationStartIsNotSingle))
+			Expect(r.Errors).To(HaveLen(2))
+		})
+
+		It("checks that lint warnings are returned with the created level", func() {
+			var r api.SubmitLevelResponse
+			g.PerformSubmitLevelRequest([]byte(`[[1,1,0,1,1],[1,4,0,2,1],[1,1,1,1,1]]`), http.StatusCreated, &r)
+			Expect(r.LevelID).NotTo(BeEmpty())
+			Expect(r.Warnings).To(HaveLen(1))
+			Expect(r.Warnings[0].Code).To(Equal(service.WarnDeadEnd))
+			Expect(r.Warnings[0].Cell).To(Equal(&game.JI{J: 3, I: 1}))
+		})
+
+		It("checks that configured tile palette and starting HP are used", func() {
+			viper.Set(config.Palette, []game.Tile{
+				{ID: game.CellOpen, Passable: true},
+				{ID: game.CellWall},
+				{ID: game.CellPlayer, Passable: true},
+				{ID: 7, Passable: true, Damage: 3},
+			})
+			viper.Set(config.StartingHP, 10)
+			defer func() {
+				viper.Set(config.Palette, nil)
+				viper.Set(config.StartingHP, 0)
+			}()
+
+			var r api.SubmitLevelResponse
+			g.PerformSubmitLevelRequest([]byte(`[[1,1,0,1],[1,4,7,1],[1,1,7,1],[1,1,7,1],[1,1,1,1]]`),
+				http.StatusCreated, &r)
+			Expect(r.Solvable).To(BeTrue())
+			Expect(r.LengthScore).To(Equal(2))
+			Expect(r.Damage).To(Equal(3))
+
+			var rErr api.ValidationErrorResponse
+			g.PerformSubmitLevelRequest([]byte(`[[1,1,0,1],[1,4,2,1],[1,1,1,1]]`), http.StatusBadRequest, &rErr)
+			Expect(rErr.Code).To(Equal(service.ErrValidationFieldHasInvalidData))
+		})
+
+		It("checks that teleporters are stored with the level and used by the solver", func() {
+			viper.Set(config.TeleportCost, 2)
+			defer viper.Set(config.TeleportCost, 0)
+
+			p := api.SubmitLevelParams{
+				Maze: [][]byte{
+					{1, 1, 1, 1, 1, 1, 1},
+					{1, 4, 12, 1, 12, 0, 0},
+					{1, 0, 1, 1, 1, 1, 1},
+					{1, 0, 0, 0, 0, 0, 0},
+					{1, 1, 1, 1, 1, 1, 1},
+				},
+				Teleporters: []game.Teleporter{{A: game.JI{J: 2, I: 1}, B: game.JI{J: 4, I: 1}}},
+			}
+			var r api.SubmitLevelResponse
+			g.PerformSubmitLevelRequest(utils.MushMarshalJSON(p), http.StatusCreated, &r)
+			Expect(r.LengthScore).To(Equal(4))
+			Expect(r.MoveCost).To(Equal(5))
+
+			var level api.GetLevelResponse
+			g.PerformGetLevelRequest(r.LevelID, http.StatusOK, &level)
+			Expect(level.Teleporters).To(Equal(p.Teleporters))
+			Expect(level.LengthScore).To(Equal(4))
+			Expect(level.MoveCost).To(Equal(5))
+
+			var solution api.GetLevelSolutionResponse
+			g.PerformGetLevelSolutionRequest(r.LevelID, "", http.StatusOK, &solution)
+			Expect(solution.Length).To(Equal(4))
+			Expect(solution.Cost).To(Equal(5))
+
+			var rErr api.ValidationErrorResponse
+			p.Teleporters = nil
+			g.PerformSubmitLevelRequest(utils.MushMarshalJSON(p), http.StatusBadRequest, &rErr)
+			Expect(rErr.Code).To(Equal(service.ErrValidationTeleporterIsUnpaired))
+			Expect(rErr.Errors).To(HaveLen(2))
+		})
+
+		It("checks that configured exit mode is used", func() {
+			viper.Set(config.ExitMode, game.ExitModeTile)
+			defer viper.Set(config.ExitMode, "")
+
+			var r api.SubmitLevelResponse
+			g.PerformSubmitLevelRequest([]byte(`[[1,1,1,1,1],[0,4,0,18,1],[1,1,1,1,1]]`), http.StatusCreated, &r)
+			Expect(r.Solvable).To(BeTrue())
+			Expect(r.LengthScore).To(Equal(2))
+		})
+
+		It("checks that configured max number of search states is used", func() {
+			viper.Set(config.MaxStates, 8)
+			defer viper.Set(config.MaxStates, 0)
+
+			// 3 passable cells and 4 HP
+			var rErr api.ValidationErrorResponse
+			g.PerformSubmitLevelRequest([]byte(`[[1,1,1,1],[1,4,0,0],[1,1,1,1]]`), http.StatusBadRequest, &rErr)
+			Expect(rErr.Code).To(Equal(service.ErrValidationStateSpaceIsTooLarge))
+			Expect(rErr.Params).To(Equal([]interface{}{float64(12), float64(8)}))
+
+			viper.Set(config.MaxStates, 12)
+			var r api.SubmitLevelResponse
+			g.PerformSubmitLevelRequest([]byte(`[[1,1,1,1],[1,4,0,0],[1,1,1,1]]`), http.StatusCreated, &r)
+			Expect(r.Solvable).To(BeTrue())
+		})
+
+		It("checks that the movement topology is stored with the level and used by the solver", func() {
+			p := api.SubmitLevelParams{
+				Maze:     [][]byte{{1, 1, 1, 0}, {1, 1, 0, 1}, {1, 4, 1, 1}, {1, 1, 1, 1}},
+				Topology: game.Topology8Way,
+			}
+			var r api.SubmitLevelResponse
+			g.PerformSubmitLevelRequest(utils.MushMarshalJSON(p), http.StatusCreated, &r)
+			Expect(r.Solvable).To(BeTrue())
+			Expect(r.LengthScore).To(Equal(2))
+
+			var level api.GetLevelResponse
+			g.PerformGetLevelRequest(r.LevelID, http.StatusOK, &level)
+			Expect(level.Topology).To(Equal(game.Topology8Way))
+
+			p.Topology = ""
+			g.PerformSubmitLevelRequest(utils.MushMarshalJSON(p), http.StatusCreated, &r)
+			Expect(r.Solvable).To(BeFalse())
+			g.PerformGetLevelRequest(r.LevelID, http.StatusOK, &level)
+			Expect(level.Topology).To(Equal(game.Topology4Way))
+
+			var rErr api.ValidationErrorResponse
+			p.Topology = "triangle"
+			g.PerformSubmitLevelRequest(utils.MushMarshalJSON(p), http.StatusBadRequest, &rErr)
+			Expect(rErr.Code).To(Equal(service.ErrValidationTopologyIsUnknown))
+		})
+
 		It("checks that creating level fails, invalid case: just wrong data", func() {
 			var r game.Error
 			g.PerformSubmitLevelRequest([]byte(`{"maze":"q"}`), http.StatusUnprocessableEntity, &r)
@@ -111,4 +331,393 @@ var _ = Describe("Testing manager API", func() {
 			Expect(r.Code).To(Equal(service.ErrValidationFieldIsNotRectangular))
 		})
 	})
+
+	Context("api.GetLevel request", func() {
+		It("checks that the stored level is returned in the submitted format", func() {
+			maze := [][]byte{
+				{1, 1, 1, 0, 1},
+				{1, 4, 2, 0, 1},
+				{1, 1, 1, 1, 1},
+			}
+			var submitted api.SubmitLevelResponse
+			g.PerformSubmitLevelRequest(utils.MushMarshalJSON(api.SubmitLevelParams{Maze: maze}),
+				http.StatusCreated, &submitted)
+
+			var r map[string]interface{}
+			g.PerformGetLevelRequest(submitted.LevelID, http.StatusOK, &r)
+			Expect(r["id"]).To(Equal(submitted.LevelID.String()))
+			Expect(r["x"]).To(BeEquivalentTo(5))
+			Expect(r["y"]).To(BeEquivalentTo(3))
+			Expect(r["maze"]).To(Equal([]interface{}{
+				[]interface{}{1., 1., 1., 0., 1.},
+				[]interface{}{1., 4., 2., 0., 1.},
+				[]interface{}{1., 1., 1., 1., 1.},
+			}))
+			Expect(r["length_score"]).To(BeEquivalentTo(submitted.LengthScore))
+			Expect(r["solvable"]).To(BeTrue())
+		})
+
+		It("checks that non-existing level is not found", func() {
+			var r game.Error
+			g.PerformGetLevelRequest(strfmt.UUID(uuid.NewV4().String()), http.StatusNotFound, &r)
+			Expect(r.Code).To(Equal(service.ErrLevelNotFound))
+		})
+
+		It("checks that level ID should be UUID", func() {
+			var r game.Error
+			g.PerformGetLevelRequest("q", http.StatusUnprocessableEntity, &r)
+			Expect(r.Code).To(Equal(service.ErrValidationRequest))
+		})
+	})
+
+	Context("api.GetLevels request", func() {
+		It("checks listing levels by pages with filtering", func() {
+			author := "author-" + uuid.NewV4().String()
+			for i := 0; i < 5; i++ {
+				p := api.SubmitLevelParams{Author: author, Maze: [][]byte{
+					append([]byte{1, 0}, bytes.Repeat([]byte{1}, i)...),
+					append([]byte{1, 4}, bytes.Repeat([]byte{0}, i)...),
+				}}
+				var r api.SubmitLevelResponse
+				g.PerformSubmitLevelRequest(utils.MushMarshalJSON(p), http.StatusCreated, &r)
+			}
+
+			query := url.Values{}
+			query.Set("author", author)
+			query.Set("sort", "x")
+			query.Set("order", "desc")
+			query.Set("limit", "2")
+
+			var r api.GetLevelsResponse
+			g.PerformGetLevelsRequest(query.Encode(), http.StatusOK, &r)
+			Expect(r.Levels).To(HaveLen(2))
+			Expect(r.Levels[0].X).To(Equal(6))
+			Expect(r.Levels[1].X).To(Equal(5))
+			Expect(r.Levels[0].Author).To(Equal(author))
+			Expect(r.Next).NotTo(BeEmpty())
+
+			var xs []int
+			for r.Next != "" {
+				next, err := url.Parse(r.Next)
+				Expect(err).NotTo(HaveOccurred())
+				r = api.GetLevelsResponse{}
+				g.PerformGetLevelsRequest(next.RawQuery, http.StatusOK, &r)
+				for _, level := range r.Levels {
+					xs = append(xs, level.X)
+				}
+			}
+			Expect(xs).To(Equal([]int{4, 3, 2}))
+		})
+
+		It("checks that listing levels fails on invalid params", func() {
+			for i, query := range []string{"sort=maze", "order=up", "limit=0", "limit=1000", "min_x=q", "cursor=q"} {
+				var r game.Error
+				g.PerformGetLevelsRequest(query, http.StatusUnprocessableEntity, &r)
+				Expect(r.Code).To(Equal(service.ErrValidationRequest), "case %d", i)
+			}
+		})
+	})
+
+	Context("api.GetLevelSolution request", func() {
+		submit := func(maze [][]byte) strfmt.UUID {
+			var r api.SubmitLevelResponse
+			g.PerformSubmitLevelRequest(utils.MushMarshalJSON(api.SubmitLevelParams{Maze: maze}), http.StatusCreated, &r)
+			Expect(r.LevelID).NotTo(BeEmpty())
+			return r.LevelID
+		}
+
+		It("checks that the minimum survivable path of the README example is returned", func() {
+			id := submit([][]byte{
+				{1, 1, 1, 1, 0, 1, 1, 1},
+				{1, 0, 0, 0, 0, 0, 0, 1},
+				{1, 0, 1, 1, 1, 3, 1, 1},
+				{1, 0, 0, 0, 1, 0, 2, 1},
+				{1, 1, 1, 0, 1, 1, 0, 1},
+				{1, 0, 0, 0, 1, 0, 0, 1},
+				{1, 0, 1, 1, 1, 0, 1, 1},
+				{1, 0, 0, 4, 0, 0, 0, 1},
+				{1, 1, 1, 1, 1, 1, 1, 1},
+			})
+			var r api.GetLevelSolutionResponse
+			g.PerformGetLevelSolutionRequest(id, "", http.StatusOK, &r)
+			Expect(r.Length).To(Equal(12))
+			Expect(r.Damage).To(Equal(3))
+			Expect(r.RemainingHP).To(Equal(1))
+			Expect(r.Path).To(HaveLen(13))
+			Expect(r.Path[0]).To(Equal(game.JI{J: 3, I: 7}))
+			Expect(r.Path[12]).To(Equal(game.JI{J: 4, I: 0}))
+		})
+
+		It("checks that the solution waits for a periodic trap", func() {
+			trap := game.PeriodicTrap{Cell: game.JI{J: 2, I: 1}, Period: 3, Phase: 2, Active: 2, Damage: 4}
+			var submitted api.SubmitLevelResponse
+			g.PerformSubmitLevelRequest(utils.MushMarshalJSON(api.SubmitLevelParams{
+				Maze:          [][]byte{{1, 1, 1, 1}, {1, 4, 3, 0}, {1, 1, 1, 1}},
+				PeriodicTraps: []game.PeriodicTrap{trap},
+			}), http.StatusCreated, &submitted)
+			Expect(submitted.LengthScore).To(Equal(4))
+
+			var r api.GetLevelSolutionResponse
+			g.PerformGetLevelSolutionRequest(submitted.LevelID, "", http.StatusOK, &r)
+			Expect(r.Length).To(Equal(4))
+			Expect(r.Waits).To(Equal(2))
+			Expect(r.Damage).To(Equal(0))
+			Expect(r.Traps).To(Equal([]game.TrapPass{{Cell: trap.Cell, Tick: 3}}))
+		})
+
+		It("checks that the solution with the min survival chance is returned", func() {
+			id := submit([][]byte{
+				{1, 1, 1, 1, 1, 1},
+				{1, 4, game.CellRandomTrap, game.CellRandomTrap, 0, 0},
+				{1, 0, 1, 1, 0, 1},
+				{1, 0, 0, 0, 0, 1},
+				{1, 1, 1, 1, 1, 1},
+			})
+			var r api.GetLevelSolutionResponse
+			g.PerformGetLevelSolutionRequest(id, "", http.StatusOK, &r)
+			Expect(r.Length).To(Equal(8))
+			Expect(r.SurvivalChance).To(Equal(1.))
+
+			g.PerformGetLevelSolutionRequest(id, "min_survival=0.7", http.StatusOK, &r)
+			Expect(r.Length).To(Equal(4))
+			Expect(r.ExpectedDamage).To(BeNumerically("~", 2))
+			Expect(r.SurvivalChance).To(BeNumerically("~", 0.75))
+
+			var rErr game.Error
+			g.PerformGetLevelSolutionRequest(id, "min_survival=2", http.StatusUnprocessableEntity, &rErr)
+			Expect(rErr.Code).To(Equal(service.ErrValidationRequest))
+		})
+
+		It("checks that the solver is selected by the query", func() {
+			id := submit([][]byte{
+				{1, 1, 1, 1, 1, 1},
+				{1, 4, game.CellRandomTrap, game.CellRandomTrap, 0, 0},
+				{1, 0, 1, 1, 0, 1},
+				{1, 0, 0, 0, 0, 1},
+				{1, 1, 1, 1, 1, 1},
+			})
+			var r api.GetLevelSolutionResponse
+			g.PerformGetLevelSolutionRequest(id, "solver=astar", http.StatusOK, &r)
+			Expect(r.Length).To(Equal(8))
+			g.PerformGetLevelSolutionRequest(id, "solver=astar&min_survival=0.7", http.StatusOK, &r)
+			Expect(r.Length).To(Equal(4))
+			g.PerformGetLevelSolutionRequest(id, "solver=bfs", http.StatusOK, &r)
+			Expect(r.Length).To(Equal(8))
+
+			var rErr game.Error
+			g.PerformGetLevelSolutionRequest(id, "solver=dfs", http.StatusUnprocessableEntity, &rErr)
+			Expect(rErr.Code).To(Equal(service.ErrValidationRequest))
+		})
+
+		It("checks that the trace of the solution search is returned", func() {
+			id := submit([][]byte{
+				{1, 1, 1, 1},
+				{1, 4, 2, 0},
+				{1, 0, 1, 1},
+				{1, 1, 1, 1},
+			})
+			var r api.GetLevelSolutionTraceResponse
+			g.PerformGetLevelSolutionTraceRequest(id, "", http.StatusOK, &r)
+			Expect(r.Solution).NotTo(BeNil())
+			Expect(r.Solution.Length).To(Equal(2))
+			Expect(r.Events[0]).To(Equal(game.TraceEvent{Type: game.TraceEnqueue, Cell: game.JI{J: 1, I: 1}, HP: 4}))
+			Expect(r.Events[1]).To(Equal(game.TraceEvent{Type: game.TraceVisit, Cell: game.JI{J: 1, I: 1}, HP: 4}))
+			Expect(r.Events).To(ContainElement(game.TraceEvent{
+				Type: game.TraceHPChange, Cell: game.JI{J: 2, I: 1}, HP: 3, FromHP: 4,
+			}))
+
+			Expect(r.Truncated).To(BeFalse())
+			events := r.Events
+
+			r = api.GetLevelSolutionTraceResponse{}
+			g.PerformGetLevelSolutionTraceRequest(id, "max_events=3", http.StatusOK, &r)
+			Expect(r.Events).To(Equal(events[:3]))
+			Expect(r.Truncated).To(BeTrue())
+			Expect(r.Solution).NotTo(BeNil())
+
+			r = api.GetLevelSolutionTraceResponse{}
+			g.PerformGetLevelSolutionTraceRequest(id, "starting_hp=1&solver=astar", http.StatusOK, &r)
+			Expect(r.Solution).To(BeNil())
+			Expect(r.Events).To(ContainElement(game.TraceEvent{
+				Type: game.TracePrune, Cell: game.JI{J: 2, I: 1}, HP: 0, Reason: game.PruneDead,
+			}))
+
+			var rErr game.Error
+			g.PerformGetLevelSolutionTraceRequest(id, "min_survival=0.5", http.StatusUnprocessableEntity, &rErr)
+			Expect(rErr.Code).To(Equal(service.ErrValidationRequest))
+			g.PerformGetLevelSolutionTraceRequest(id, "max_events=0", http.StatusUnprocessableEntity, &rErr)
+			Expect(rErr.Code).To(Equal(service.ErrValidationRequest))
+		})
+
+		It("checks that positions of enemies are returned with the solution", func() {
+			enemy := game.Enemy{Route: []game.JI{{J: 3, I: 2}, {J: 3, I: 1}}, Rate: 1, Damage: 4}
+			var submitted api.SubmitLevelResponse
+			g.PerformSubmitLevelRequest(utils.MushMarshalJSON(api.SubmitLevelParams{
+				Maze:    [][]byte{{1, 1, 1, 1, 1, 1}, {1, 1, 4, 0, 0, 0}, {1, 1, 1, 0, 1, 1}, {1, 1, 1, 1, 1, 1}},
+				Enemies: []game.Enemy{enemy},
+			}), http.StatusCreated, &submitted)
+
+			var level api.GetLevelResponse
+			g.PerformGetLevelRequest(submitted.LevelID, http.StatusOK, &level)
+			Expect(level.Enemies).To(Equal([]game.Enemy{enemy}))
+
+			var r api.GetLevelSolutionResponse
+			g.PerformGetLevelSolutionRequest(submitted.LevelID, "", http.StatusOK, &r)
+			Expect(r.Length).To(Equal(4))
+			Expect(r.Waits).To(Equal(1))
+			Expect(r.EnemyHits).To(BeEmpty())
+			Expect(r.Enemies).To(HaveLen(5))
+			Expect(r.Enemies[1]).To(Equal([]game.JI{{J: 3, I: 1}}))
+		})
+
+		It("checks that the step count and the move cost are returned for slow terrain", func() {
+			id := submit([][]byte{
+				{1, 1, 1, 1, 1},
+				{1, 4, game.CellMud, game.CellWater, 0},
+				{1, 1, 1, 1, 1},
+			})
+			var r api.GetLevelSolutionResponse
+			g.PerformGetLevelSolutionRequest(id, "", http.StatusOK, &r)
+			Expect(r.Length).To(Equal(3))
+			Expect(r.Cost).To(Equal(6))
+		})
+
+		It("checks that the level without exits has no solution", func() {
+			id := submit([][]byte{
+				{1, 1, 1},
+				{1, 4, 1},
+				{1, 1, 1},
+			})
+			var r game.Error
+			g.PerformGetLevelSolutionRequest(id, "", http.StatusUnprocessableEntity, &r)
+			Expect(r.Code).To(Equal(service.ErrLevelHasNoExit))
+		})
+
+		It("checks that the level without survivable path has no solution", func() {
+			id := submit([][]byte{
+				{1, 1, 1, 1, 1},
+				{1, 4, 3, 3, 0},
+				{1, 1, 1, 1, 1},
+			})
+			var r game.Error
+			g.PerformGetLevelSolutionRequest(id, "", http.StatusUnprocessableEntity, &r)
+			Expect(r.Code).To(Equal(service.ErrNoSurvivablePath))
+		})
+
+		It("checks that solution of non-existing level is not found", func() {
+			var r game.Error
+			g.PerformGetLevelSolutionRequest(strfmt.UUID(uuid.NewV4().String()), "", http.StatusNotFound, &r)
+			Expect(r.Code).To(Equal(service.ErrLevelNotFound))
+		})
+	})
+
+	Context("api.GetLevelRoutes request", func() {
+		It("checks that the fast and the safe routes of the README example are returned", func() {
+			var submitted api.SubmitLevelResponse
+			g.PerformSubmitLevelRequest([]byte(`[[1,1,1,1,0,1,1,1],[1,0,0,0,0,0,0,1],[1,0,1,1,1,3,1,1],`+
+				`[1,0,0,0,1,0,2,1],[1,1,1,0,1,1,0,1],[1,0,0,0,1,0,0,1],[1,0,1,1,1,0,1,1],[1,0,0,4,0,0,0,1],`+
+				`[1,1,1,1,1,1,1,1]]`), http.StatusCreated, &submitted)
+
+			var r api.GetLevelRoutesResponse
+			g.PerformGetLevelRoutesRequest(submitted.LevelID, http.StatusOK, &r)
+			Expect(r.Routes).To(HaveLen(2))
+			Expect(r.Routes[0].Length).To(Equal(12))
+			Expect(r.Routes[0].Damage).To(Equal(3))
+			Expect(r.Routes[0].Path).To(HaveLen(13))
+			Expect(r.Routes[1].Length).To(Equal(16))
+			Expect(r.Routes[1].Damage).To(Equal(0))
+			Expect(r.Routes[1].Path).To(HaveLen(17))
+		})
+
+		It("checks that routes of non-existing level are not found", func() {
+			var r game.Error
+			g.PerformGetLevelRoutesRequest(strfmt.UUID(uuid.NewV4().String()), http.StatusNotFound, &r)
+			Expect(r.Code).To(Equal(service.ErrLevelNotFound))
+		})
+	})
+
+	Context("api.GetLevelHP request", func() {
+		It("checks that the minimum starting HP and the HP curve are returned", func() {
+			var submitted api.SubmitLevelResponse
+			g.PerformSubmitLevelRequest([]byte(`[[1,1,1,1,1,1],[1,4,3,2,3,0],[1,1,1,1,1,1]]`),
+				http.StatusCreated, &submitted)
+			Expect(submitted.Solvable).To(BeFalse())
+
+			var r game.HPAnalysis
+			g.PerformGetLevelHPRequest(submitted.LevelID, http.StatusOK, &r)
+			Expect(r.MinStartingHP).To(Equal(6))
+			Expect(r.Curve).To(HaveLen(6))
+			Expect(r.Curve[5].Length).To(Equal(4))
+
+			By("checking that the solution accepts the starting HP", func() {
+				var rErr game.Error
+				g.PerformGetLevelSolutionRequest(submitted.LevelID, "", http.StatusUnprocessableEntity, &rErr)
+				Expect(rErr.Code).To(Equal(service.ErrNoSurvivablePath))
+
+				var solution api.GetLevelSolutionResponse
+				g.PerformGetLevelSolutionRequest(submitted.LevelID, "starting_hp=6", http.StatusOK, &solution)
+				Expect(solution.Length).To(Equal(4))
+				Expect(solution.RemainingHP).To(Equal(1))
+
+				g.PerformGetLevelSolutionRequest(submitted.LevelID, "starting_hp=-1", http.StatusUnprocessableEntity, &rErr)
+				Expect(rErr.Code).To(Equal(service.ErrValidationRequest))
+
+				// the configured starting HP is 4
+				g.PerformGetLevelSolutionRequest(submitted.LevelID, "starting_hp="+strconv.Itoa(4*api.MaxStartingHPFactor),
+					http.StatusOK, &solution)
+				g.PerformGetLevelSolutionRequest(submitted.LevelID, "starting_hp="+strconv.Itoa(4*api.MaxStartingHPFactor+1),
+					http.StatusUnprocessableEntity, &rErr)
+				Expect(rErr.Code).To(Equal(service.ErrValidationRequest))
+				g.PerformGetLevelSolutionTraceRequest(submitted.LevelID, "starting_hp=1000000000",
+					http.StatusUnprocessableEntity, &rErr)
+				Expect(rErr.Code).To(Equal(service.ErrValidationRequest))
+			})
+		})
+	})
+
+	Context("api.RescoreLevels", func() {
+		It("checks that scores of stored levels are recalculated", func() {
+			s := service.Get()
+			Expect(s.Storage.RemoveAll()).To(Succeed())
+			solvable := api.SubmitLevelParams{Maze: [][]byte{
+				{1, 1, 1, 1, 1},
+				{1, 4, 0, 2, 0},
+				{1, 1, 1, 1, 1},
+			}}
+			unsolvable := api.SubmitLevelParams{Maze: [][]byte{
+				{1, 1, 1, 1, 1},
+				{1, 4, 1, 0, 0},
+				{1, 1, 1, 1, 1},
+			}}
+			var ids []strfmt.UUID
+			for i, p := range []api.SubmitLevelParams{solvable, unsolvable} {
+				position, errs := p.ToPosition(game.DefaultRules())
+				Expect(errs).To(BeEmpty(), "case %d", i)
+				// stale scores as left by the migration adding them or calculated with other rules
+				level := position.ToStorage()
+				level.LengthScore, level.MoveCost, level.Damage, level.Solvable = 7, 7, 7, i == 1
+				id, err := s.Storage.AddLevel(level)
+				Expect(err).NotTo(HaveOccurred(), "case %d", i)
+				ids = append(ids, id)
+			}
+
+			count, err := api.RescoreLevels()
+			Expect(err).NotTo(HaveOccurred())
+			Expect(count).To(Equal(2))
+
+			level, err := s.Storage.GetLevel(ids[0])
+			Expect(err).NotTo(HaveOccurred())
+			Expect(level.Solvable).To(BeTrue())
+			Expect(level.LengthScore).To(Equal(3))
+			Expect(level.MoveCost).To(Equal(3))
+			Expect(level.Damage).To(Equal(1))
+
+			level, err = s.Storage.GetLevel(ids[1])
+			Expect(err).NotTo(HaveOccurred())
+			Expect(level.Solvable).To(BeFalse())
+			Expect(level.LengthScore).To(Equal(0))
+			Expect(level.MoveCost).To(Equal(0))
+			Expect(level.Damage).To(Equal(0))
+		})
+	})
 })
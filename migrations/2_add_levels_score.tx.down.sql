@@ -0,0 +1,4 @@
+ALTER TABLE levels
+    DROP COLUMN IF EXISTS length_score,
+    DROP COLUMN IF EXISTS damage,
+    DROP COLUMN IF EXISTS solvable;
\ No newline at end of file
@@ -0,0 +1,5 @@
+-- scores of existing levels are calculated by running the service with --rescore after migrating
+ALTER TABLE levels
+    ADD COLUMN IF NOT EXISTS length_score INT     NOT NULL DEFAULT 0,
+    ADD COLUMN IF NOT EXISTS damage       INT     NOT NULL DEFAULT 0,
+    ADD COLUMN IF NOT EXISTS solvable     BOOLEAN NOT NULL DEFAULT FALSE;
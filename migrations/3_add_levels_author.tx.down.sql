@@ -0,0 +1,7 @@
+DROP INDEX IF EXISTS levels_author_idx;
+DROP INDEX IF EXISTS levels_length_score_idx;
+DROP INDEX IF EXISTS levels_created_at_idx;
+
+ALTER TABLE levels
+    DROP COLUMN IF EXISTS author,
+    DROP COLUMN IF EXISTS created_at;
\ No newline at end of file
@@ -0,0 +1,7 @@
+ALTER TABLE levels
+    ADD COLUMN IF NOT EXISTS author     TEXT        NOT NULL DEFAULT '',
+    ADD COLUMN IF NOT EXISTS created_at TIMESTAMPTZ NOT NULL DEFAULT NOW();
+
+CREATE INDEX IF NOT EXISTS levels_created_at_idx ON levels (created_at, id);
+CREATE INDEX IF NOT EXISTS levels_length_score_idx ON levels (length_score, id);
+CREATE INDEX IF NOT EXISTS levels_author_idx ON levels (author);
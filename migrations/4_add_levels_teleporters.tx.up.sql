@@ -0,0 +1,2 @@
+ALTER TABLE levels
+    ADD COLUMN IF NOT EXISTS teleporters JSONB;
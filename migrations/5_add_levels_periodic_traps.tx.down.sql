@@ -0,0 +1,2 @@
+ALTER TABLE levels
+    DROP COLUMN IF EXISTS periodic_traps;
\ No newline at end of file
@@ -0,0 +1,2 @@
+ALTER TABLE levels
+    DROP COLUMN IF EXISTS enemies;
\ No newline at end of file
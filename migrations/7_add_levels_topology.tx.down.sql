@@ -0,0 +1,2 @@
+ALTER TABLE levels
+    DROP COLUMN IF EXISTS topology;
\ No newline at end of file
@@ -0,0 +1,2 @@
+ALTER TABLE levels
+    ADD COLUMN IF NOT EXISTS topology TEXT NOT NULL DEFAULT '4way';
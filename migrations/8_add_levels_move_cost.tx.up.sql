@@ -0,0 +1,4 @@
+-- length_score is the step count of the minimum survivable path since move costs are stored separately,
+-- scores of existing levels are recalculated by running the service with --rescore after migrating
+ALTER TABLE levels
+    ADD COLUMN IF NOT EXISTS move_cost INT NOT NULL DEFAULT 0;
\ No newline at end of file
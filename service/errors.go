@@ -9,4 +9,35 @@ const (
 	ErrValidationFieldIsNotRectangular
 	ErrValidationFieldHasInvalidData
 	ErrStorageFailed
+	ErrLevelNotFound
+	ErrLevelHasNoStart
+	ErrLevelHasNoExit
+	ErrNoSurvivablePath
+	ErrSolverFailed
+	ErrValidationCellIsOutOfRange
+	ErrValidationStartIsNotSingle
+	ErrValidationBorderIsNotEnclosed
+	ErrValidationNoExit
+	ErrValidationNoSurvivablePath
+	ErrConfigurationInvalid
+	ErrValidationTeleporterIsUnpaired
+	ErrValidationTeleporterIsAmbiguous
+	ErrValidationPeriodicTrapIsInvalid
+	ErrValidationEnemyIsInvalid
+	ErrValidationTopologyIsUnknown
+	ErrValidationTooManyPotions
+	ErrValidationTeleporterIsInvalid
+	ErrSolverBudgetExceeded
+	ErrValidationStateSpaceIsTooLarge
+)
+
+// lint warning codes
+const (
+	WarnUnreachableRegion = 100 + iota
+	WarnUselessTrap
+	WarnDeadEnd
+	WarnUnreachableExit
+	WarnMultipleStarts
+	WarnLintBudgetExceeded
+	WarnLintSkipped
 )
@@ -1,10 +1,15 @@
 package storage
 
 import (
+	"errors"
+
 	"github.com/go-openapi/strfmt"
 	"github.com/mtfelian/gjg-test-task/storage/model"
 )
 
+// ErrLevelNotFound is returned when the requested level does not exist in the storage
+var ErrLevelNotFound = errors.New("level not found")
+
 // Keeper abstracts data storage
 type Keeper interface {
 	ApplyMigrations(path, migrateCommand string) error
@@ -12,4 +17,6 @@ type Keeper interface {
 	AddLevel(levelData model.Level) (strfmt.UUID, error)
 	RemoveAll() error
 	GetLevels(p model.GetLevelsParams) (levels []model.Level, err error)
+	GetLevel(id strfmt.UUID) (*model.Level, error)
+	UpdateLevelScore(level model.Level) error
 }
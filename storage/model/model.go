@@ -1,11 +1,113 @@
 package model
 
 import (
+	"time"
+
 	uuid "github.com/satori/go.uuid"
 )
 
-// GetLevelsParams represents parameters for requesting levels
+// levels sort fields
+const (
+	SortByCreatedAt   = "created_at"
+	SortByLengthScore = "length_score"
+	SortByX           = "x"
+	SortByY           = "y"
+)
+
+// IsValidSortField returns true if levels can be sorted by the given field
+func IsValidSortField(field string) bool {
+	switch field {
+	case SortByCreatedAt, SortByLengthScore, SortByX, SortByY:
+		return true
+	}
+	return false
+}
+
+// LevelsCursor points to the last level of the previous page, it contains values of all sort fields
+type LevelsCursor struct {
+	ID          uuid.UUID `json:"id"`
+	CreatedAt   time.Time `json:"created_at"`
+	LengthScore int       `json:"length_score"`
+	X           int       `json:"x"`
+	Y           int       `json:"y"`
+}
+
+// NewLevelsCursor returns a cursor pointing to the given level
+func NewLevelsCursor(level Level) *LevelsCursor {
+	return &LevelsCursor{
+		ID:          level.ID,
+		CreatedAt:   level.CreatedAt,
+		LengthScore: level.LengthScore,
+		X:           level.X,
+		Y:           level.Y,
+	}
+}
+
+// Value of the given sort field
+func (c LevelsCursor) Value(field string) interface{} {
+	switch field {
+	case SortByLengthScore:
+		return c.LengthScore
+	case SortByX:
+		return c.X
+	case SortByY:
+		return c.Y
+	}
+	return c.CreatedAt
+}
+
+// GetLevelsParams represents parameters for requesting levels.
+// Zero values of the filtering fields mean no filtering by them.
 type GetLevelsParams struct {
+	MinX, MaxX int
+	MinY, MaxY int
+
+	// score range, only solvable levels are returned if any of them is set
+	MinScore, MaxScore int
+
+	Author string
+
+	CreatedAfter, CreatedBefore time.Time
+
+	// SortBy is one of SortBy* constants, default is SortByCreatedAt
+	SortBy     string
+	Descending bool
+
+	// After is a cursor to return the levels following it
+	After *LevelsCursor
+	Limit int
+}
+
+// Teleporter is a pair of linked teleporter cells of the level
+type Teleporter struct {
+	AX   int `json:"ax"`
+	AY   int `json:"ay"`
+	BX   int `json:"bx"`
+	BY   int `json:"by"`
+	Cost int `json:"cost"`
+}
+
+// PeriodicTrap is a trap cell of the level dealing damage on a cycle
+type PeriodicTrap struct {
+	X      int `json:"x"`
+	Y      int `json:"y"`
+	Period int `json:"period"`
+	Phase  int `json:"phase"`
+	Active int `json:"active"`
+	Damage int `json:"damage"`
+}
+
+// Point is a cell of the level
+type Point struct {
+	X int `json:"x"`
+	Y int `json:"y"`
+}
+
+// Enemy patrols the level along the route
+type Enemy struct {
+	Route  []Point `json:"route"`
+	Rate   int     `json:"rate"`
+	Damage int     `json:"damage"`
 }
 
 // Level represents price level
@@ -16,4 +118,18 @@ type Level struct {
 	X    int       `pg:"x,notnull"`
 	Y    int       `pg:"y,notnull"`
 	Maze []byte    `pg:"maze,notnull"`
+
+	// minimum survivable path data, calculated on submit
+	LengthScore int  `pg:"length_score,notnull,use_zero"` // number of moves
+	MoveCost    int  `pg:"move_cost,notnull,use_zero"`    // total move cost in ticks
+	Damage      int  `pg:"damage,notnull,use_zero"`
+	Solvable    bool `pg:"solvable,notnull,use_zero"`
+
+	Author    string    `pg:"author,notnull,use_zero"`
+	CreatedAt time.Time `pg:"created_at,notnull"`
+
+	Teleporters   []Teleporter   `pg:"teleporters,type:jsonb"`
+	PeriodicTraps []PeriodicTrap `pg:"periodic_traps,type:jsonb"`
+	Enemies       []Enemy        `pg:"enemies,type:jsonb"`
+	Topology      string         `pg:"topology,notnull,use_zero"`
 }
@@ -2,8 +2,10 @@ package storage
 
 import (
 	"context"
+	"fmt"
 	"os"
 	"strings"
+	"time"
 
 	"github.com/go-openapi/strfmt"
 	"github.com/go-pg/migrations/v8"
@@ -125,19 +127,87 @@ func (keeper *PostgresKeeper) Close() error { return keeper.pdb.Close() }
 // AddLevel to the storage
 func (keeper *PostgresKeeper) AddLevel(level model.Level) (strfmt.UUID, error) {
 	level.ID = uuid.NewV4()
+	if level.CreatedAt.IsZero() {
+		level.CreatedAt = time.Now()
+	}
 	_, err := keeper.pdb.Model(&level).Insert()
 	return strfmt.UUID(level.ID.String()), err
 }
 
-// AddLevel to the storage
+// GetLevel by the given id, returns ErrLevelNotFound if there is no such level
+func (keeper *PostgresKeeper) GetLevel(id strfmt.UUID) (*model.Level, error) {
+	levelID, err := uuid.FromString(id.String())
+	if err != nil {
+		return nil, err
+	}
+	level := &model.Level{ID: levelID}
+	if err = keeper.pdb.Model(level).WherePK().Select(); err != nil {
+		if err == pg.ErrNoRows {
+			return nil, ErrLevelNotFound
+		}
+		return nil, err
+	}
+	return level, nil
+}
+
+// GetLevels from the storage according to the given params
 func (keeper *PostgresKeeper) GetLevels(p model.GetLevelsParams) (levels []model.Level, err error) {
-	err = keeper.modifyLevelsQuery(keeper.pdb.Model(&levels), p).Select()
+	query := keeper.modifyLevelsQuery(keeper.pdb.Model(&levels), p)
+
+	sortBy, order, op := p.SortBy, "ASC", ">"
+	if sortBy == "" {
+		sortBy = model.SortByCreatedAt
+	}
+	if p.Descending {
+		order, op = "DESC", "<"
+	}
+	if p.After != nil {
+		query.Where(fmt.Sprintf("(?, id) %s (?, ?)", op), pg.Ident(sortBy), p.After.Value(sortBy), p.After.ID)
+	}
+	query.OrderExpr(fmt.Sprintf("? %s, id %s", order, order), pg.Ident(sortBy))
+	if p.Limit > 0 {
+		query.Limit(p.Limit)
+	}
+
+	err = query.Select()
 	return
 }
 
+// UpdateLevelScore sets the minimum survivable path data of the stored level with the ID of the given one,
+// returns ErrLevelNotFound if there is no such level
+func (keeper *PostgresKeeper) UpdateLevelScore(level model.Level) error {
+	res, err := keeper.pdb.Model(&level).Column("length_score", "move_cost", "damage", "solvable").WherePK().Update()
+	if err != nil {
+		return err
+	}
+	if res.RowsAffected() == 0 {
+		return ErrLevelNotFound
+	}
+	return nil
+}
+
 // modifyLevelsQuery with given params p
 func (keeper *PostgresKeeper) modifyLevelsQuery(query *orm.Query, p model.GetLevelsParams) *orm.Query {
-	// construct query by p
+	for _, filter := range []struct {
+		condition string
+		value     interface{}
+		apply     bool
+	}{
+		{"x >= ?", p.MinX, p.MinX > 0},
+		{"x <= ?", p.MaxX, p.MaxX > 0},
+		{"y >= ?", p.MinY, p.MinY > 0},
+		{"y <= ?", p.MaxY, p.MaxY > 0},
+		{"solvable = ?", true, p.MinScore > 0 || p.MaxScore > 0},
+		{"length_score >= ?", p.MinScore, p.MinScore > 0},
+		{"length_score <= ?", p.MaxScore, p.MaxScore > 0},
+		{"author = ?", p.Author, p.Author != ""},
+		{"created_at >= ?", p.CreatedAfter, !p.CreatedAfter.IsZero()},
+		{"created_at < ?", p.CreatedBefore, !p.CreatedBefore.IsZero()},
+	} {
+		if filter.apply {
+			query.Where(filter.condition, filter.value)
+		}
+	}
 	return query
 }
 
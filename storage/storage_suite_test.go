@@ -13,6 +13,7 @@ import (
 	"github.com/mtfelian/utils"
 	. "github.com/onsi/ginkgo"
 	. "github.com/onsi/gomega"
+	uuid "github.com/satori/go.uuid"
 	"github.com/sirupsen/logrus"
 	"github.com/spf13/viper"
 )
@@ -78,7 +79,7 @@ var _ = Describe("testing service API", func() {
 					0, 0, 0, 1,
 					0, 1, 0, 1,
 					0, 1, 0, 1,
-				}},
+				}, LengthScore: 3, Damage: 1, Solvable: true},
 			}
 			By("creating newLevels", func() {
 				ids = make([]strfmt.UUID, len(newLevels))
@@ -95,6 +96,104 @@ var _ = Describe("testing service API", func() {
 				Expect(err).NotTo(HaveOccurred())
 				Expect(levels).To(HaveLen(3))
 			})
+
+			By("getting levels by ID", func() {
+				for i, id := range ids {
+					level, err := s.Storage.GetLevel(id)
+					Expect(err).NotTo(HaveOccurred())
+					Expect(level.ID.String()).To(Equal(id.String()))
+					Expect(level.Maze).To(Equal(newLevels[i].Maze))
+					Expect(level.LengthScore).To(Equal(newLevels[i].LengthScore))
+					Expect(level.Damage).To(Equal(newLevels[i].Damage))
+					Expect(level.Solvable).To(Equal(newLevels[i].Solvable))
+				}
+			})
+		})
+
+		It("checks filtering, sorting and paginating levels", func() {
+			created := time.Now().Add(-time.Hour).Truncate(time.Second)
+			for i := 0; i < 10; i++ {
+				author := "alice"
+				if i%2 == 1 {
+					author = "bob"
+				}
+				_, err := s.Storage.AddLevel(model.Level{
+					X: 2 + i, Y: 2, Maze: make([]byte, 2*(2+i)),
+					LengthScore: 10 - i, Solvable: i != 0,
+					Author:    author,
+					CreatedAt: created.Add(time.Duration(i) * time.Minute),
+				})
+				Expect(err).NotTo(HaveOccurred())
+			}
+
+			By("filtering by dimensions, score, author and creation time", func() {
+				type tcs struct {
+					p        model.GetLevelsParams
+					expected int
+				}
+				for i, tc := range []tcs{
+					{p: model.GetLevelsParams{MinX: 5, MaxX: 8}, expected: 4},
+					{p: model.GetLevelsParams{MinY: 3}, expected: 0},
+					{p: model.GetLevelsParams{MinScore: 5}, expected: 5},
+					{p: model.GetLevelsParams{MaxScore: 5}, expected: 5},
+					{p: model.GetLevelsParams{Author: "bob"}, expected: 5},
+					{p: model.GetLevelsParams{CreatedAfter: created.Add(5 * time.Minute)}, expected: 5},
+					{p: model.GetLevelsParams{CreatedBefore: created.Add(5 * time.Minute)}, expected: 5},
+					{p: model.GetLevelsParams{Author: "alice", MinX: 6}, expected: 2},
+				} {
+					levels, err := s.Storage.GetLevels(tc.p)
+					Expect(err).NotTo(HaveOccurred(), "case %d", i)
+					Expect(levels).To(HaveLen(tc.expected), "case %d", i)
+				}
+			})
+
+			By("paginating sorted by length score", func() {
+				p := model.GetLevelsParams{SortBy: model.SortByLengthScore, Descending: true, Limit: 4}
+				var scores []int
+				for {
+					levels, err := s.Storage.GetLevels(p)
+					Expect(err).NotTo(HaveOccurred())
+					for _, level := range levels {
+						scores = append(scores, level.LengthScore)
+					}
+					if len(levels) < p.Limit {
+						break
+					}
+					p.After = model.NewLevelsCursor(levels[len(levels)-1])
+				}
+				Expect(scores).To(Equal([]int{10, 9, 8, 7, 6, 5, 4, 3, 2, 1}))
+			})
+		})
+
+		It("checks getting non-existing level", func() {
+			level, err := s.Storage.GetLevel(strfmt.UUID(uuid.NewV4().String()))
+			Expect(err).To(Equal(storage.ErrLevelNotFound))
+			Expect(level).To(BeNil())
+		})
+
+		It("checks updating level score", func() {
+			id, err := s.Storage.AddLevel(model.Level{X: 3, Y: 2, Maze: []byte{
+				0, 1, 0,
+				0, 0, 4,
+			}, Author: "author"})
+			Expect(err).NotTo(HaveOccurred())
+
+			levelID, err := uuid.FromString(id.String())
+			Expect(err).NotTo(HaveOccurred())
+			Expect(s.Storage.UpdateLevelScore(model.Level{ID: levelID, LengthScore: 2, MoveCost: 3, Damage: 1, Solvable: true})).
+				To(Succeed())
+
+			level, err := s.Storage.GetLevel(id)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(level.LengthScore).To(Equal(2))
+			Expect(level.MoveCost).To(Equal(3))
+			Expect(level.Damage).To(Equal(1))
+			Expect(level.Solvable).To(BeTrue())
+			Expect(level.Author).To(Equal("author"))
+			Expect(level.Maze).To(HaveLen(6))
+
+			Expect(s.Storage.UpdateLevelScore(model.Level{ID: uuid.NewV4(), Solvable: true})).
+				To(Equal(storage.ErrLevelNotFound))
 		})
 	})
 })